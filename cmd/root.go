@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/urfave/cli/v2"
@@ -9,7 +10,10 @@ import (
 	"go-contracts/controller"
 	"go-contracts/cycle"
 	"go-contracts/database"
+	"go-contracts/models"
 	"go-contracts/service"
+	"go-contracts/signer"
+	"go-contracts/synchronizer/node"
 	"go-contracts/util"
 	"os"
 	"os/signal"
@@ -103,6 +107,54 @@ func StartServer(gitCommit, gitDate string) *cli.App {
 			Flags:       globalFlags,
 			Action:      cycle.LifecycleCmd(runAirdropWatcher), // 绑定空投监听服务
 		},
+		{
+			Name:        "airdrop-reindex",
+			Usage:       "重新索引旧版空投事件",
+			Description: "删除没有 log_index 的旧版空投事件和空投检查点，之后启动的空投监听服务从部署区块重新回填（执行前先停止 airdrop-watch）",
+			Flags:       globalFlags,
+			Action:      runAirdropReindex, // 一次性任务
+		},
+		{
+			Name:        "reconcile-balances",
+			Usage:       "核对ERC20余额",
+			Description: "抽查 erc20_balances 中的余额，与链上 balanceOf 结果比对并报告偏差",
+			Flags: append(globalFlags, []cli.Flag{
+				&cli.IntFlag{
+					Name:  "limit",
+					Usage: "随机抽取核对的记录数（0 表示全部）",
+					Value: 100,
+				},
+			}...),
+			Action: runReconcileBalances, // 一次性任务
+		},
+		{
+			Name:        "tx",
+			Usage:       "查询、加速或取消已发送的交易",
+			Description: "按交易哈希操作本服务发送的交易，结果以 JSON 输出",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "status",
+					Usage:     "查询交易状态（待确认的交易立即检查一次回执）",
+					ArgsUsage: "<交易哈希>",
+					Flags:     globalFlags,
+					Action:    runTxCommand(false, service.Service.GetTransaction),
+				},
+				{
+					Name:      "speed-up",
+					Usage:     "以相同 nonce、更高费用重新发送交易",
+					ArgsUsage: "<交易哈希>",
+					Flags:     globalFlags,
+					Action:    runTxCommand(true, service.Service.SpeedUpTransaction),
+				},
+				{
+					Name:      "cancel",
+					Usage:     "以相同 nonce 发送 0 金额的自转账，取消交易",
+					ArgsUsage: "<交易哈希>",
+					Flags:     globalFlags,
+					Action:    runTxCommand(true, service.Service.CancelTransaction),
+				},
+			},
+		},
 		{
 			Name:        "migrate",
 				Usage:       "执行数据库迁移",
@@ -123,6 +175,114 @@ func versionWithCommit(commit, date string) string {
 	return version
 }
 
+// runAirdropReindex 删除旧版空投事件和空投检查点，由空投监听服务重新回填
+func runAirdropReindex(ctx *cli.Context) error {
+	// 1. 加载配置
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		util.Log.Error("加载配置失败", "err", err)
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	// 2. 初始化数据库
+	db, err := database.NewDb(ctx.Context, &cfg.MasterDB)
+	if err != nil {
+		return fmt.Errorf("init db: %w", err)
+	}
+	defer db.Close()
+
+	// 3. 删除旧记录和检查点
+	deleted, err := db.ResetLegacyAirdropEvents()
+	if err != nil {
+		return fmt.Errorf("reset airdrop events: %w", err)
+	}
+	util.Log.Info("已删除旧版空投事件和空投检查点，启动 airdrop-watch 后从部署区块重新回填", "deleted", deleted)
+	return nil
+}
+
+// runReconcileBalances 核对ERC20余额
+func runReconcileBalances(ctx *cli.Context) error {
+	// 1. 加载配置
+	cfg, err := config.LoadConfig(ctx)
+	if err != nil {
+		util.Log.Error("加载配置失败", "err", err)
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	// 2. 初始化数据库与区块链客户端
+	db, err := database.NewDb(ctx.Context, &cfg.MasterDB)
+	if err != nil {
+		return fmt.Errorf("init db: %w", err)
+	}
+	defer db.Close()
+
+	ethClient, err := node.DialEthClientPool(ctx.Context, &cfg.RPC)
+	if err != nil {
+		return fmt.Errorf("dial rpc: %w", err)
+	}
+	defer ethClient.Close()
+
+	// 3. 核对余额，存在偏差时以非零状态退出
+	report, err := service.ReconcileBalances(ctx.Context, db, ethClient, ctx.Int("limit"))
+	if err != nil {
+		return fmt.Errorf("reconcile balances: %w", err)
+	}
+	if len(report.Drifts) > 0 {
+		return cli.Exit(fmt.Sprintf("发现 %d 条余额偏差（共核对 %d 条，区块 %d）", len(report.Drifts), report.Checked, report.BlockNumber), 1)
+	}
+	return nil
+}
+
+// runTxCommand 按交易哈希查询、加速或取消已发送的交易，needSigner 为 true 时需要交易签名器
+func runTxCommand(needSigner bool, fn func(svc service.Service, ctx context.Context, txHash string) (*models.SentTransaction, error)) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		if ctx.NArg() != 1 {
+			return cli.Exit("需要指定交易哈希", 1)
+		}
+
+		// 1. 加载配置
+		cfg, err := config.LoadConfig(ctx)
+		if err != nil {
+			util.Log.Error("加载配置失败", "err", err)
+			return fmt.Errorf("load config: %w", err)
+		}
+
+		// 2. 初始化数据库与区块链客户端
+		db, err := database.NewDb(ctx.Context, &cfg.MasterDB)
+		if err != nil {
+			return fmt.Errorf("init db: %w", err)
+		}
+		defer db.Close()
+
+		ethClient, err := node.DialEthClientPool(ctx.Context, &cfg.RPC)
+		if err != nil {
+			return fmt.Errorf("dial rpc: %w", err)
+		}
+		defer ethClient.Close()
+
+		// 3. 创建交易签名器（仅加速、取消需要）
+		var txSigner signer.Signer
+		if needSigner {
+			if txSigner, err = signer.New(ctx.Context, cfg.Signer); err != nil {
+				return fmt.Errorf("init signer: %w", err)
+			}
+		}
+
+		// 4. 执行并输出交易记录
+		svc := service.New(util.Validator{}, ethClient, db, cfg.Tx, cfg.Indexer.ERC20Contracts, txSigner)
+		result, err := fn(svc, ctx.Context, ctx.Args().First())
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+		out, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+}
+
 // runMigrations 数据库迁移
 func runMigrations(ctx *cli.Context) error {
 	util.Log.Info("执行数据库迁移...")
@@ -47,10 +47,69 @@ type HTTPServerConfig struct {
 
 // IndexerConfig 索引服务配置
 type IndexerConfig struct {
-	Interval int `yaml:"interval" env:"INDEXER_INTERVAL"` // 同步间隔（秒）
+	Interval   int    `yaml:"interval" env:"INDEXER_INTERVAL"`                                  // 同步间隔（秒）
+	StartBlock uint64 `yaml:"start_block" mapstructure:"start_block" env:"INDEXER_START_BLOCK"` // 起始区块号（无检查点且 blocks 表为空时使用）
+	// 确认数：只处理低于链头 N 个区块的数据（索引服务与空投监听共用）
+	Confirmations uint64 `yaml:"confirmations" mapstructure:"confirmations" env:"INDEXER_CONFIRMATIONS"`
+	RangeSize     uint64 `yaml:"range_size" mapstructure:"range_size"` // 每个同步范围的区块数
+	Workers       int    `yaml:"workers" mapstructure:"workers"`       // 追赶阶段并发拉取的范围数
+	// 需要索引 Transfer、Approval 日志的 ERC20 合约地址列表
+	ERC20Contracts []string `yaml:"erc20_contracts" mapstructure:"erc20_contracts"`
 	// 其他配置：如区块链 RPC 地址、合约地址等
 }
 
+// AirdropContractConfig 单个空投合约部署
+type AirdropContractConfig struct {
+	Address    string `yaml:"address"`                                // 合约地址
+	StartBlock uint64 `yaml:"start_block" mapstructure:"start_block"` // 合约部署区块（无检查点时从此处回填）
+}
+
+// AirdropConfig 空投事件监听配置
+type AirdropConfig struct {
+	// 监听的空投合约列表，为空时监听 AIRDROP_CONTRACT_ADDRESS（起始区块为 StartBlock）
+	Contracts  []AirdropContractConfig `yaml:"contracts"`
+	StartBlock uint64                  `yaml:"start_block" mapstructure:"start_block"` // 合约部署区块（无检查点时从此处回填）
+	RangeSize  uint64                  `yaml:"range_size" mapstructure:"range_size"`   // 回填时每次查询日志的区块数
+	// 轮询间隔（秒），节点不支持订阅（HTTP 节点）时定期查询 eth_getLogs
+	PollInterval int `yaml:"poll_interval" mapstructure:"poll_interval"`
+	MaxRestarts  int `yaml:"max_restarts" mapstructure:"max_restarts"` // 订阅连续失败的最大重试次数，超过后关闭服务
+}
+
+// RPCConfig 区块链节点连接池配置
+type RPCConfig struct {
+	Endpoints      []string `yaml:"endpoints"`                                        // 节点地址列表（按优先级排序，为空时使用 RAW_URL）
+	HealthInterval int      `yaml:"health_interval" mapstructure:"health_interval"`   // 健康检查间隔（秒）
+	MaxHeadLag     uint64   `yaml:"max_head_lag" mapstructure:"max_head_lag"`         // 允许落后最高节点的区块数，超过则视为不健康
+	MaxRetries     int      `yaml:"max_retries" mapstructure:"max_retries"`           // 临时错误的最大重试次数
+	RetryBackoffMs int      `yaml:"retry_backoff_ms" mapstructure:"retry_backoff_ms"` // 重试初始退避时间（毫秒），每次重试翻倍
+	BatchSize      int      `yaml:"batch_size" mapstructure:"batch_size"`             // 单个 JSON-RPC 批量请求的最大调用数
+}
+
+// TxConfig 交易发送配置
+type TxConfig struct {
+	GasMarginPercent uint64 `yaml:"gas_margin_percent" mapstructure:"gas_margin_percent"` // Gas 估算的安全余量（百分比）
+	// 每单位 Gas 的最高费用（gwei），EIP-1559 交易为 maxFeePerGas，传统交易为 gasPrice，0 表示不限制
+	MaxFeeGwei float64 `yaml:"max_fee_gwei" mapstructure:"max_fee_gwei"`
+	MaxTipGwei float64 `yaml:"max_tip_gwei" mapstructure:"max_tip_gwei"` // 最高小费 maxPriorityFeePerGas（gwei），0 表示不限制
+	// 交易跟踪：达到确认数后记录最终状态；交易池中查不到且超过 DropTimeout 秒未打包的交易视为被丢弃
+	Confirmations uint64 `yaml:"confirmations"`
+	PollInterval  int    `yaml:"poll_interval" mapstructure:"poll_interval"` // 查询交易回执的间隔（秒）
+	DropTimeout   int    `yaml:"drop_timeout" mapstructure:"drop_timeout"`   // 判定交易被丢弃的等待时间（秒）
+}
+
+// SignerConfig 交易签名配置
+type SignerConfig struct {
+	Type    string `yaml:"type"`                             // 签名方式：key（原始私钥）、keystore（加密 keystore 文件）、remote（远程签名服务）
+	KeyEnv  string `yaml:"key_env" mapstructure:"key_env"`   // type=key：保存十六进制私钥的环境变量名
+	KeyFile string `yaml:"key_file" mapstructure:"key_file"` // type=key：保存十六进制私钥的文件（优先于 KeyEnv）
+	// type=keystore：加密的 keystore JSON 文件及其密码文件
+	KeystoreFile   string `yaml:"keystore_file" mapstructure:"keystore_file"`
+	PassphraseFile string `yaml:"passphrase_file" mapstructure:"passphrase_file"`
+	// type=remote：支持 eth_signTransaction 的远程签名服务地址及签名账户
+	RemoteURL string `yaml:"remote_url" mapstructure:"remote_url"`
+	Address   string `yaml:"address"`
+}
+
 type Config struct {
 	MasterDB     DBConfig         `yaml:"masterdb"`     // 数据库配置
 	MigrationDir string           `yaml:"migrationdir"` // 迁移文件目录
@@ -58,6 +117,10 @@ type Config struct {
 	Redis        RedisConfig      `yaml:"redis"`        // Redis配置
 	Kafka        KafkaConfig      `yaml:"kafka"`        // Kafka配置
 	Indexer      IndexerConfig    `yaml:"indexer"`      // 索引服务配置
+	RPC          RPCConfig        `yaml:"rpc"`          // 区块链节点配置
+	Airdrop      AirdropConfig    `yaml:"airdrop"`      // 空投事件监听配置
+	Tx           TxConfig         `yaml:"tx"`           // 交易发送配置
+	Signer       SignerConfig     `yaml:"signer"`       // 交易签名配置
 }
 
 const defaultConfigFileName = "config.yaml"
@@ -108,6 +171,24 @@ func LoadConfig(ctx *cli.Context) (*Config, error) {
 	v.SetDefault("httpserver.read_timeout", 10)  // 默认读取超时 10秒
 	v.SetDefault("httpserver.write_timeout", 10) // 默认写入超时 10秒
 	v.SetDefault("httpserver.idle_timeout", 30)  // 默认空闲超时 30秒
+	// ===== 索引服务默认值 =====
+	v.SetDefault("indexer.erc20_contracts", []string{ERC20_CONTRACT_ADDRESS})
+	v.SetDefault("airdrop.range_size", 2000)
+	v.SetDefault("airdrop.poll_interval", 5)
+	v.SetDefault("airdrop.max_restarts", 10)
+	v.SetDefault("tx.gas_margin_percent", 20)
+	v.SetDefault("tx.confirmations", 3)
+	v.SetDefault("tx.poll_interval", 5)
+	v.SetDefault("tx.drop_timeout", 600)
+	v.SetDefault("signer.type", "key")
+	v.SetDefault("signer.key_env", "PRIVATE_KEY")
+	// ===== 区块链节点默认值 =====
+	v.SetDefault("rpc.endpoints", []string{RAW_URL})
+	v.SetDefault("rpc.health_interval", 15)
+	v.SetDefault("rpc.max_head_lag", 5)
+	v.SetDefault("rpc.max_retries", 3)
+	v.SetDefault("rpc.retry_backoff_ms", 200)
+	v.SetDefault("rpc.batch_size", 100)
 
 	// 3. 支持环境变量（自动大写并替换 . 为 _）
 	v.SetEnvPrefix("APP") // 环境变量前缀 APP_
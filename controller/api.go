@@ -11,6 +11,7 @@ import (
 	"go-contracts/database"
 	"go-contracts/router"
 	"go-contracts/service"
+	"go-contracts/signer"
 	"go-contracts/synchronizer/node"
 	"go-contracts/util"
 	"net"
@@ -20,8 +21,10 @@ import (
 
 // ApiService HTTP 服务实现 cycle.Service 接口
 type API struct {
-	db        *database.DB    //  数据库连接
-	redisPool *database.Redis // Redis
+	db        *database.DB       //  数据库连接
+	redisPool *database.Redis    // Redis
+	ethClient node.EthClient     // 区块链客户端
+	txTracker *service.TxTracker // 已发送交易跟踪
 	apiServer *httputil.HTTPServer
 	//	kafkaConsumer sarama.Consumer // Kafka 消费者（sarama）
 	localCache *sync.Map // 本地缓存（sync.Map）
@@ -52,12 +55,20 @@ func (a *API) initFromConfig(c *cli.Context, cfg *config.Config) error {
 	var v util.Validator
 
 	// 初始化区块链客户端
-	ethClient, err := node.DialEthClient(*c, config.RAW_URL)
+	ethClient, err := node.DialEthClientPool(c.Context, &cfg.RPC)
 	if err != nil {
 		return fmt.Errorf("连接区块链节点失败: %w", err)
 	}
+	a.ethClient = ethClient
+	// 创建交易签名器
+	txSigner, err := signer.New(c.Context, cfg.Signer)
+	if err != nil {
+		return fmt.Errorf("创建交易签名器失败: %w", err)
+	}
 	// 创建业务服务实例，传入区块对应链信息
-	svc := service.New(v, ethClient)
+	svc := service.New(v, ethClient, a.db, cfg.Tx, cfg.Indexer.ERC20Contracts, txSigner)
+	// 创建交易跟踪服务（随 API 服务启动）
+	a.txTracker = service.NewTxTracker(a.db, ethClient, cfg.Tx)
 	// 初始化路由
 	a.router = router.InitRouter(cfg.HTTPServer, cfg, svc)
 
@@ -116,6 +127,11 @@ func (a *API) Start(ctx context.Context) error {
 		}
 	}()
 
+	// 启动交易跟踪
+	if err := a.txTracker.Start(ctx); err != nil {
+		return fmt.Errorf("交易跟踪服务启动失败: %w", err)
+	}
+
 	util.Log.Info("API服务已启动")
 	return nil
 }
@@ -134,21 +150,33 @@ func (a *API) Stop(ctx context.Context) error {
 		}
 	}
 
-	// 2. 关闭数据库连接
+	// 2. 停止交易跟踪（在关闭数据库和区块链客户端之前）
+	if a.txTracker != nil {
+		if err := a.txTracker.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("交易跟踪服务停止失败: %w", err))
+		}
+	}
+
+	// 3. 关闭数据库连接
 	if a.db != nil {
 		if err := a.db.Close(); err != nil {
 			errs = append(errs, fmt.Errorf("数据库关闭失败: %w", err))
 		}
 	}
 
-	// 3. 关闭Redis连接
+	// 4. 关闭Redis连接
 	if a.redisPool != nil {
 		if err := a.redisPool.Close(); err != nil {
 			errs = append(errs, fmt.Errorf("Redis关闭失败: %w", err))
 		}
 	}
 
-	// 4. 清理本地缓存
+	// 5. 关闭区块链客户端
+	if a.ethClient != nil {
+		a.ethClient.Close()
+	}
+
+	// 6. 清理本地缓存
 	if a.localCache != nil {
 		a.localCache.Range(func(key, value interface{}) bool {
 			a.localCache.Delete(key)
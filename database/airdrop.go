@@ -0,0 +1,120 @@
+package database
+
+import (
+	"fmt"
+	"go-contracts/models"
+	"go-contracts/util"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UpsertAirdropEvent 写入空投事件，以 (transaction_hash, log_index) 去重
+// 事件已存在时更新区块信息和代币地址，同一事件多次保存结果不变
+func UpsertAirdropEvent(tx *gorm.DB, event *models.AirdropEvent) error {
+	// deleted_at 取插入值（NULL），已被软删除的同一事件重新保存后恢复可见
+	columns := []string{"block_hash", "block_number", "block_time", "updated_at", "deleted_at"}
+	if event.TokenResolved {
+		// 代币地址解析失败时不覆盖已解析的结果
+		columns = append(columns, "token_address", "token_resolved")
+	}
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "transaction_hash"}, {Name: "log_index"}},
+		DoUpdates: clause.AssignmentColumns(columns),
+	}).Create(event).Error
+	if err != nil {
+		return fmt.Errorf("写入空投事件失败（%s:%d）: %w", event.TransactionHash.Hex(), event.LogIndex, err)
+	}
+	return nil
+}
+
+// ListUnresolvedAirdropEvents 查询空投合约中代币地址未解析的 ERC20 空投事件，按区块顺序，最多 limit 条
+// BNB 空投的代币地址固定，不需要解析
+func (d *DB) ListUnresolvedAirdropEvents(contractAddress common.Address, limit int) ([]*models.AirdropEvent, error) {
+	var events []*models.AirdropEvent
+	err := d.Where("contract_address = ? AND event_type = ? AND token_resolved = ?", contractAddress, models.AirdropTypeERC20, false).
+		Order("block_number, log_index").Limit(limit).Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询代币未解析的空投事件失败（%s）: %w", contractAddress.Hex(), err)
+	}
+	return events, nil
+}
+
+// CountUnresolvedAirdropEvents 统计代币地址未解析的 ERC20 空投事件
+func (d *DB) CountUnresolvedAirdropEvents() (int64, error) {
+	var count int64
+	err := d.Model(&models.AirdropEvent{}).Where("event_type = ? AND token_resolved = ?", models.AirdropTypeERC20, false).Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("统计代币未解析的空投事件失败: %w", err)
+	}
+	return count, nil
+}
+
+// ResolveAirdropEventToken 记录空投事件解析出的代币地址
+func (d *DB) ResolveAirdropEventToken(event *models.AirdropEvent, token common.Address) error {
+	err := d.Model(event).Updates(map[string]interface{}{"token_address": token, "token_resolved": true}).Error
+	if err != nil {
+		return fmt.Errorf("更新空投事件代币地址失败（%s:%d）: %w", event.TransactionHash.Hex(), event.LogIndex, err)
+	}
+	return nil
+}
+
+// addAirdropEventLogIndex 旧版 airdrop_events 没有 log_index，先补充可为空的列，旧记录的 log_index 为 NULL，
+// 不影响唯一索引的创建；旧记录需通过 airdrop-reindex 命令删除后由空投监听服务重新回填
+func addAirdropEventLogIndex(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&models.AirdropEvent{}) || migrator.HasColumn(&models.AirdropEvent{}, "LogIndex") {
+		return nil
+	}
+	if err := migrator.AddColumn(&models.AirdropEvent{}, "LogIndex"); err != nil {
+		return fmt.Errorf("添加 airdrop_events.log_index 失败: %w", err)
+	}
+	util.Log.Warn("airdrop_events 已添加 log_index，旧记录需执行 airdrop-reindex 重新索引")
+	return nil
+}
+
+// addAirdropEventTokenResolved 旧版 airdrop_events 没有 token_resolved，补充该列后将已有记录标记为已解析，
+// 避免旧记录（尤其是代币地址固定的 BNB 空投）被当作未解析的事件重新查询代币地址
+func addAirdropEventTokenResolved(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&models.AirdropEvent{}) || migrator.HasColumn(&models.AirdropEvent{}, "TokenResolved") {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Migrator().AddColumn(&models.AirdropEvent{}, "TokenResolved"); err != nil {
+			return fmt.Errorf("添加 airdrop_events.token_resolved 失败: %w", err)
+		}
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Unscoped().Model(&models.AirdropEvent{}).Update("token_resolved", true).Error; err != nil {
+			return fmt.Errorf("标记已有空投事件的代币地址为已解析失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// CountLegacyAirdropEvents 统计没有 log_index 的旧版空投事件
+func (d *DB) CountLegacyAirdropEvents() (int64, error) {
+	var count int64
+	if err := d.Model(&models.AirdropEvent{}).Where("log_index IS NULL").Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("统计旧版空投事件失败: %w", err)
+	}
+	return count, nil
+}
+
+// ResetLegacyAirdropEvents 删除没有 log_index 的旧版空投事件和空投检查点，返回删除的事件数
+// 空投监听服务下次启动时从部署区块重新回填（已有的新版记录按唯一索引去重）
+func (d *DB) ResetLegacyAirdropEvents() (int64, error) {
+	var deleted int64
+	err := d.Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Where("log_index IS NULL").Delete(&models.AirdropEvent{})
+		if result.Error != nil {
+			return fmt.Errorf("删除旧版空投事件失败: %w", result.Error)
+		}
+		deleted = result.RowsAffected
+		if err := tx.Where("name LIKE ?", models.CheckpointAirdropPrefix+"%").Delete(&models.SyncCheckpoint{}).Error; err != nil {
+			return fmt.Errorf("删除空投检查点失败: %w", err)
+		}
+		return nil
+	})
+	return deleted, err
+}
@@ -0,0 +1,210 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"go-contracts/models"
+	"gorm.io/gorm"
+)
+
+// CreateAirdropJob 在同一事务中创建空投任务、分片及其接收者
+// items 按分片顺序排列，依次按各分片的 ItemCount 归属到对应分片
+func (d *DB) CreateAirdropJob(job *models.AirdropJob, chunks []*models.AirdropJobChunk, items []*models.AirdropJobItem) error {
+	return d.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(job).Error; err != nil {
+			return fmt.Errorf("创建空投任务失败: %w", err)
+		}
+		offset := 0
+		for _, chunk := range chunks {
+			chunk.JobID = job.ID
+			if err := tx.Create(chunk).Error; err != nil {
+				return fmt.Errorf("创建空投分片失败（%d）: %w", chunk.ChunkIndex, err)
+			}
+			for _, item := range items[offset : offset+chunk.ItemCount] {
+				item.JobID = job.ID
+				item.ChunkID = chunk.ID
+			}
+			offset += chunk.ItemCount
+		}
+		if offset != len(items) {
+			return fmt.Errorf("空投分片接收者数量（%d）与接收者总数（%d）不一致", offset, len(items))
+		}
+		if err := tx.CreateInBatches(items, 500).Error; err != nil {
+			return fmt.Errorf("创建空投接收者失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetAirdropJob 查询空投任务及其分片、接收者，不存在时返回 nil
+func (d *DB) GetAirdropJob(id uint) (*models.AirdropJob, []*models.AirdropJobChunk, []*models.AirdropJobItem, error) {
+	var job models.AirdropJob
+	err := d.First(&job, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("查询空投任务失败（%d）: %w", id, err)
+	}
+	var chunks []*models.AirdropJobChunk
+	if err := d.Where("job_id = ?", id).Order("chunk_index").Find(&chunks).Error; err != nil {
+		return nil, nil, nil, fmt.Errorf("查询空投分片失败（%d）: %w", id, err)
+	}
+	var items []*models.AirdropJobItem
+	if err := d.Where("job_id = ?", id).Order("id").Find(&items).Error; err != nil {
+		return nil, nil, nil, fmt.Errorf("查询空投接收者失败（%d）: %w", id, err)
+	}
+	return &job, chunks, items, nil
+}
+
+// SubmitAirdropChunk 记录分片已发送的交易及实际 Gas 上限，分片内接收者标记为已提交
+func (d *DB) SubmitAirdropChunk(chunk *models.AirdropJobChunk, txHash string, nonce uint64) error {
+	return d.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(chunk).Updates(map[string]interface{}{"tx_hash": txHash, "nonce": nonce, "gas_limit": chunk.GasLimit}).Error
+		if err != nil {
+			return fmt.Errorf("更新空投分片失败（%d）: %w", chunk.ID, err)
+		}
+		err = tx.Model(&models.AirdropJobItem{}).Where("chunk_id = ?", chunk.ID).
+			Updates(map[string]interface{}{"tx_hash": txHash, "status": models.AirdropItemSubmitted}).Error
+		if err != nil {
+			return fmt.Errorf("更新空投接收者失败（分片 %d）: %w", chunk.ID, err)
+		}
+		return refreshAirdropJob(tx, chunk.JobID)
+	})
+}
+
+// FailAirdropChunk 记录分片失败原因，分片内尚未到账的接收者标记为失败
+func (d *DB) FailAirdropChunk(chunk *models.AirdropJobChunk, message string) error {
+	return d.Transaction(func(tx *gorm.DB) error {
+		return failAirdropChunk(tx, chunk, message)
+	})
+}
+
+func failAirdropChunk(tx *gorm.DB, chunk *models.AirdropJobChunk, message string) error {
+	if err := tx.Model(chunk).Update("error", message).Error; err != nil {
+		return fmt.Errorf("更新空投分片失败（%d）: %w", chunk.ID, err)
+	}
+	err := tx.Model(&models.AirdropJobItem{}).
+		Where("chunk_id = ? AND status IN ?", chunk.ID, []string{models.AirdropItemPending, models.AirdropItemSubmitted}).
+		Update("status", models.AirdropItemFailed).Error
+	if err != nil {
+		return fmt.Errorf("更新空投接收者失败（分片 %d）: %w", chunk.ID, err)
+	}
+	return refreshAirdropJob(tx, chunk.JobID)
+}
+
+// SetAirdropJobError 记录空投任务的失败原因
+func (d *DB) SetAirdropJobError(jobID uint, message string) error {
+	err := d.Model(&models.AirdropJob{}).Where("id = ?", jobID).Update("error", message).Error
+	if err != nil {
+		return fmt.Errorf("更新空投任务失败（%d）: %w", jobID, err)
+	}
+	return nil
+}
+
+// MatchAirdropEvent 将已索引的空投事件匹配到对应的任务接收者，并更新任务状态
+// 按 (交易哈希, 接收者, 金额) 匹配，同一交易中重复的接收者按顺序依次匹配
+func MatchAirdropEvent(tx *gorm.DB, event *models.AirdropEvent) error {
+	var item models.AirdropJobItem
+	err := tx.Where("tx_hash = ? AND recipient = ? AND amount = ? AND (log_index IS NULL OR log_index = ?)",
+		event.TransactionHash.Hex(), event.Recipient.Hex(), event.Amount, event.LogIndex).
+		Order("log_index IS NULL, id").First(&item).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil // 不是通过本服务发起的空投
+	}
+	if err != nil {
+		return fmt.Errorf("匹配空投接收者失败（%s）: %w", event.TransactionHash.Hex(), err)
+	}
+
+	logIndex := event.LogIndex
+	err = tx.Model(&item).Updates(map[string]interface{}{
+		"status":       models.AirdropItemConfirmed,
+		"block_number": event.BlockNumber,
+		"log_index":    &logIndex,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("更新空投接收者失败（%d）: %w", item.ID, err)
+	}
+	return refreshAirdropJob(tx, item.JobID)
+}
+
+// refreshAirdropJob 根据接收者状态重新计算各分片及任务的状态
+func refreshAirdropJob(tx *gorm.DB, jobID uint) error {
+	var counts []struct {
+		ChunkID uint
+		Status  string
+		Count   int
+	}
+	err := tx.Model(&models.AirdropJobItem{}).Select("chunk_id, status, COUNT(*) AS count").
+		Where("job_id = ?", jobID).Group("chunk_id, status").Scan(&counts).Error
+	if err != nil {
+		return fmt.Errorf("统计空投接收者状态失败（%d）: %w", jobID, err)
+	}
+
+	jobCounts := make(map[string]int)
+	chunkCounts := make(map[uint]map[string]int)
+	for _, c := range counts {
+		jobCounts[c.Status] += c.Count
+		if chunkCounts[c.ChunkID] == nil {
+			chunkCounts[c.ChunkID] = make(map[string]int)
+		}
+		chunkCounts[c.ChunkID][c.Status] += c.Count
+	}
+
+	for chunkID, byStatus := range chunkCounts {
+		err = tx.Model(&models.AirdropJobChunk{}).Where("id = ?", chunkID).Update("status", airdropStatus(byStatus)).Error
+		if err != nil {
+			return fmt.Errorf("更新空投分片状态失败（%d）: %w", chunkID, err)
+		}
+	}
+	err = tx.Model(&models.AirdropJob{}).Where("id = ?", jobID).Update("status", airdropStatus(jobCounts)).Error
+	if err != nil {
+		return fmt.Errorf("更新空投任务状态失败（%d）: %w", jobID, err)
+	}
+	return nil
+}
+
+// airdropStatus 由接收者状态计数汇总出任务（分片）状态
+func airdropStatus(byStatus map[string]int) string {
+	total := 0
+	for _, n := range byStatus {
+		total += n
+	}
+	switch {
+	case byStatus[models.AirdropItemPending] == total:
+		return models.AirdropJobPending
+	case byStatus[models.AirdropItemConfirmed] == total:
+		return models.AirdropJobCompleted
+	case byStatus[models.AirdropItemFailed] == total:
+		return models.AirdropJobFailed
+	case byStatus[models.AirdropItemConfirmed]+byStatus[models.AirdropItemFailed] == total:
+		return models.AirdropJobPartial
+	}
+	return models.AirdropJobSubmitted
+}
+
+// revertAirdropMatches 链重组回滚时，将分叉点之上已匹配的接收者恢复为已提交状态，等待重新匹配
+func revertAirdropMatches(tx *gorm.DB, forkPoint uint64) error {
+	var jobIDs []uint
+	err := tx.Model(&models.AirdropJobItem{}).Distinct("job_id").
+		Where("status = ? AND block_number > ?", models.AirdropItemConfirmed, forkPoint).Pluck("job_id", &jobIDs).Error
+	if err != nil {
+		return fmt.Errorf("查询待回滚的空投接收者失败: %w", err)
+	}
+	if len(jobIDs) == 0 {
+		return nil
+	}
+
+	err = tx.Model(&models.AirdropJobItem{}).
+		Where("status = ? AND block_number > ?", models.AirdropItemConfirmed, forkPoint).
+		Updates(map[string]interface{}{"status": models.AirdropItemSubmitted, "block_number": 0, "log_index": nil}).Error
+	if err != nil {
+		return fmt.Errorf("回滚空投接收者状态失败: %w", err)
+	}
+	for _, jobID := range jobIDs {
+		if err := refreshAirdropJob(tx, jobID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
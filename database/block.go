@@ -0,0 +1,42 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"go-contracts/models"
+	"gorm.io/gorm"
+)
+
+// BlockHashByNumber 查询已保存区块的哈希，不存在时 ok 为 false
+func (d *DB) BlockHashByNumber(number uint64) (hash string, ok bool, err error) {
+	var block models.Block
+	err = d.Select("block_hash").Where("block_number = ?", number).First(&block).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("查询区块哈希失败（区块号: %d）: %w", number, err)
+	}
+	return block.BlockHash, true, nil
+}
+
+// RollbackAbove 删除分叉点之上的区块及其派生数据（空投事件、ERC20交易），并撤销对应的余额变动
+// tx 应为事务句柄，调用方负责在同一事务中回退检查点
+func RollbackAbove(tx *gorm.DB, forkPoint uint64) error {
+	if err := tx.Where("block_number > ?", forkPoint).Delete(&models.Block{}).Error; err != nil {
+		return fmt.Errorf("删除孤块失败: %w", err)
+	}
+	if err := tx.Unscoped().Where("block_number > ?", forkPoint).Delete(&models.AirdropEvent{}).Error; err != nil {
+		return fmt.Errorf("删除孤块空投事件失败: %w", err)
+	}
+	if err := revertAirdropMatches(tx, forkPoint); err != nil {
+		return err
+	}
+	if err := RevertERC20Balances(tx, forkPoint); err != nil {
+		return err
+	}
+	if err := tx.Where("block_number > ?", forkPoint).Delete(&models.ERC20Transaction{}).Error; err != nil {
+		return fmt.Errorf("删除孤块ERC20交易失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"go-contracts/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetCheckpoint 读取指定名称的同步检查点，不存在时返回 nil
+func (d *DB) GetCheckpoint(name string) (*models.SyncCheckpoint, error) {
+	var checkpoint models.SyncCheckpoint
+	err := d.Where("name = ?", name).First(&checkpoint).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询同步检查点失败（%s）: %w", name, err)
+	}
+	return &checkpoint, nil
+}
+
+// SaveCheckpoint 写入或更新同步检查点
+// tx 可以是事务句柄，以保证检查点与业务数据同时提交
+func SaveCheckpoint(tx *gorm.DB, name string, blockNumber uint64) error {
+	checkpoint := &models.SyncCheckpoint{
+		Name:        name,
+		BlockNumber: blockNumber,
+	}
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"block_number", "updated_at"}),
+	}).Create(checkpoint).Error
+	if err != nil {
+		return fmt.Errorf("保存同步检查点失败（%s）: %w", name, err)
+	}
+	return nil
+}
+
+// RewindCheckpoints 将名称以 prefix 开头且超过 blockNumber 的检查点回退到 blockNumber
+// 用于链重组回滚后让依赖这些区块的任务重新处理
+func RewindCheckpoints(tx *gorm.DB, prefix string, blockNumber uint64) error {
+	err := tx.Model(&models.SyncCheckpoint{}).
+		Where("name LIKE ? AND block_number > ?", prefix+"%", blockNumber).
+		Update("block_number", blockNumber).Error
+	if err != nil {
+		return fmt.Errorf("回退同步检查点失败（%s*）: %w", prefix, err)
+	}
+	return nil
+}
+
+// MaxBlockNumber 查询 blocks 表中已保存的最大区块号，表为空时 ok 为 false
+func (d *DB) MaxBlockNumber() (number uint64, ok bool, err error) {
+	var max *uint64
+	if err := d.Model(&models.Block{}).Select("MAX(block_number)").Scan(&max).Error; err != nil {
+		return 0, false, fmt.Errorf("查询最大区块号失败: %w", err)
+	}
+	if max == nil {
+		return 0, false, nil
+	}
+	return *max, true, nil
+}
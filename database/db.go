@@ -54,10 +54,24 @@ func NewDb(ctx context.Context, cfg *config.DBConfig) (*DB, error) {
 		return nil, fmt.Errorf("数据库Ping失败: %w", err)
 	}
 
-	// 5. 自动迁移表结构
+	// 5. 自动迁移表结构（先为旧数据补充无法直接迁移的列）
+	if err := addAirdropEventLogIndex(db); err != nil {
+		return nil, err
+	}
+	if err := addAirdropEventTokenResolved(db); err != nil {
+		return nil, err
+	}
 	if err := db.AutoMigrate(
 		&models.Block{},
 		&models.AirdropEvent{},
+		&models.SyncCheckpoint{},
+		&models.ERC20Transaction{},
+		&models.ERC20Balance{},
+		&models.AirdropJob{},
+		&models.AirdropJobChunk{},
+		&models.AirdropJobItem{},
+		&models.NonceReservation{},
+		&models.SentTransaction{},
 	); err != nil {
 		return nil, fmt.Errorf("数据库表结构迁移失败: %w", err)
 	}
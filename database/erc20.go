@@ -0,0 +1,177 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"go-contracts/models"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// balanceKey 余额表的唯一键
+type balanceKey struct {
+	contract, account string
+}
+
+// SaveERC20Transactions 保存 ERC20 交易记录，并在同一事务中更新余额
+// 以 (tx_hash, log_index) 去重，重复同步同一范围时既不会产生重复记录，也不会重复计入余额
+func SaveERC20Transactions(tx *gorm.DB, txs []*models.ERC20Transaction) error {
+	if err := removePendingERC20Transactions(tx, txs); err != nil {
+		return err
+	}
+
+	inserted := make([]*models.ERC20Transaction, 0, len(txs))
+	for _, t := range txs {
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tx_hash"}, {Name: "log_index"}},
+			DoNothing: true,
+		}).Create(t)
+		if result.Error != nil {
+			return fmt.Errorf("保存ERC20交易记录失败（%s:%d）: %w", t.TxHash, t.LogIndex, result.Error)
+		}
+		if result.RowsAffected > 0 {
+			inserted = append(inserted, t)
+		}
+	}
+	return applyTransfers(tx, inserted, false)
+}
+
+// SavePendingERC20Transaction 记录本服务发送的 ERC20 交易（仅限索引服务索引的合约），区块信息在索引到链上日志后补全
+func (d *DB) SavePendingERC20Transaction(t *models.ERC20Transaction) error {
+	t.Pending = true
+	err := d.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tx_hash"}, {Name: "log_index"}},
+		DoNothing: true,
+	}).Create(t).Error
+	if err != nil {
+		return fmt.Errorf("记录ERC20交易失败（%s）: %w", t.TxHash, err)
+	}
+	return nil
+}
+
+// removePendingERC20Transactions 删除即将由索引结果替换的待确认记录
+func removePendingERC20Transactions(tx *gorm.DB, txs []*models.ERC20Transaction) error {
+	if len(txs) == 0 {
+		return nil
+	}
+	hashes := make([]string, len(txs))
+	for i, t := range txs {
+		hashes[i] = t.TxHash
+	}
+	err := tx.Where("tx_hash IN ? AND pending = ?", hashes, true).Delete(&models.ERC20Transaction{}).Error
+	if err != nil {
+		return fmt.Errorf("删除待确认的ERC20交易记录失败: %w", err)
+	}
+	return nil
+}
+
+// RevertERC20Balances 撤销分叉点之上已计入余额的转账，需在删除交易记录之前调用
+func RevertERC20Balances(tx *gorm.DB, forkPoint uint64) error {
+	var txs []*models.ERC20Transaction
+	err := tx.Where("block_number > ? AND transaction_type = ?", forkPoint, models.ERC20TypeTransfer).Find(&txs).Error
+	if err != nil {
+		return fmt.Errorf("查询待回滚的ERC20转账失败: %w", err)
+	}
+	return applyTransfers(tx, txs, true)
+}
+
+// applyTransfers 按转账记录汇总每个 (合约, 账户) 的余额变动并写入余额表
+// revert 为 true 时反向计入；零地址（铸造、销毁）不记录余额
+func applyTransfers(tx *gorm.DB, txs []*models.ERC20Transaction, revert bool) error {
+	deltas := make(map[balanceKey]*big.Int)
+	blocks := make(map[balanceKey]uint64)
+	var keys []balanceKey
+	add := func(contract, account string, amount *big.Int, block uint64) {
+		if common.HexToAddress(account) == (common.Address{}) {
+			return
+		}
+		key := balanceKey{contract: contract, account: account}
+		if _, ok := deltas[key]; !ok {
+			deltas[key] = new(big.Int)
+			keys = append(keys, key)
+		}
+		deltas[key].Add(deltas[key], amount)
+		if block > blocks[key] {
+			blocks[key] = block
+		}
+	}
+
+	for _, t := range txs {
+		if t.TransactionType != models.ERC20TypeTransfer || !t.Status {
+			continue
+		}
+		amount, ok := new(big.Int).SetString(t.Amount, 10)
+		if !ok {
+			return fmt.Errorf("无效的转账金额（%s:%d）: %s", t.TxHash, t.LogIndex, t.Amount)
+		}
+		if revert {
+			amount.Neg(amount)
+		}
+		add(t.ContractAddress, t.From, new(big.Int).Neg(amount), t.BlockNumber)
+		add(t.ContractAddress, t.To, amount, t.BlockNumber)
+	}
+
+	for _, key := range keys {
+		if deltas[key].Sign() == 0 {
+			continue
+		}
+		if err := addBalance(tx, key, deltas[key], blocks[key], revert); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addBalance 将变动累加到 (合约, 账户) 的余额上，记录不存在时新建
+func addBalance(tx *gorm.DB, key balanceKey, delta *big.Int, block uint64, revert bool) error {
+	var balance models.ERC20Balance
+	err := tx.Where("contract_address = ? AND account = ?", key.contract, key.account).First(&balance).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("查询ERC20余额失败（%s/%s）: %w", key.contract, key.account, err)
+	}
+
+	current := new(big.Int)
+	if balance.Balance != "" {
+		if _, ok := current.SetString(balance.Balance, 10); !ok {
+			return fmt.Errorf("无效的ERC20余额（%s/%s）: %s", key.contract, key.account, balance.Balance)
+		}
+	}
+	current.Add(current, delta)
+
+	balance.ContractAddress = key.contract
+	balance.Account = key.account
+	balance.Balance = current.String()
+	if !revert {
+		balance.BlockNumber = block
+	}
+	if err := tx.Save(&balance).Error; err != nil {
+		return fmt.Errorf("更新ERC20余额失败（%s/%s）: %w", key.contract, key.account, err)
+	}
+	return nil
+}
+
+// SampleERC20Balances 随机抽取最多 limit 条余额记录
+func (d *DB) SampleERC20Balances(limit int) ([]*models.ERC20Balance, error) {
+	random := "RANDOM()"
+	if d.Dialector.Name() == "mysql" {
+		random = "RAND()"
+	}
+	var balances []*models.ERC20Balance
+	if err := d.Order(random).Limit(limit).Find(&balances).Error; err != nil {
+		return nil, fmt.Errorf("抽取ERC20余额失败: %w", err)
+	}
+	return balances, nil
+}
+
+// ListERC20Balances 按 ID 顺序分页查询余额记录
+func (d *DB) ListERC20Balances(afterID uint, limit int) ([]*models.ERC20Balance, error) {
+	var balances []*models.ERC20Balance
+	err := d.Where("id > ?", afterID).Order("id").Limit(limit).Find(&balances).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询ERC20余额失败: %w", err)
+	}
+	return balances, nil
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"fmt"
+	"go-contracts/models"
+)
+
+// ListNonceReservations 查询账户的在途 nonce，按 nonce 升序
+func (d *DB) ListNonceReservations(address string) ([]*models.NonceReservation, error) {
+	var reservations []*models.NonceReservation
+	if err := d.Where("address = ?", address).Order("nonce").Find(&reservations).Error; err != nil {
+		return nil, fmt.Errorf("查询在途nonce失败（%s）: %w", address, err)
+	}
+	return reservations, nil
+}
+
+// ReserveNonce 记录分配给交易的 nonce
+func (d *DB) ReserveNonce(address string, nonce uint64) error {
+	if err := d.Create(&models.NonceReservation{Address: address, Nonce: nonce}).Error; err != nil {
+		return fmt.Errorf("记录在途nonce失败（%s:%d）: %w", address, nonce, err)
+	}
+	return nil
+}
+
+// MarkNonceSent 记录使用该 nonce 发送成功的交易哈希
+func (d *DB) MarkNonceSent(address string, nonce uint64, txHash string) error {
+	err := d.Model(&models.NonceReservation{}).Where("address = ? AND nonce = ?", address, nonce).
+		Update("tx_hash", txHash).Error
+	if err != nil {
+		return fmt.Errorf("更新在途nonce失败（%s:%d）: %w", address, nonce, err)
+	}
+	return nil
+}
+
+// ReleaseNonce 删除未被使用的 nonce 记录
+func (d *DB) ReleaseNonce(address string, nonce uint64) error {
+	err := d.Where("address = ? AND nonce = ?", address, nonce).Delete(&models.NonceReservation{}).Error
+	if err != nil {
+		return fmt.Errorf("删除在途nonce失败（%s:%d）: %w", address, nonce, err)
+	}
+	return nil
+}
+
+// PruneNonces 删除 below 之前的 nonce 记录（节点已经接收）
+func (d *DB) PruneNonces(address string, below uint64) error {
+	err := d.Where("address = ? AND nonce < ?", address, below).Delete(&models.NonceReservation{}).Error
+	if err != nil {
+		return fmt.Errorf("清理在途nonce失败（%s）: %w", address, err)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"go-contracts/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SaveSentTransaction 记录已发送的交易，重复记录同一交易哈希时忽略
+func (d *DB) SaveSentTransaction(t *models.SentTransaction) error {
+	err := d.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tx_hash"}},
+		DoNothing: true,
+	}).Create(t).Error
+	if err != nil {
+		return fmt.Errorf("记录已发送交易失败（%s）: %w", t.TxHash, err)
+	}
+	return nil
+}
+
+// GetSentTransaction 按交易哈希查询已发送的交易，不存在时返回 nil
+func (d *DB) GetSentTransaction(txHash string) (*models.SentTransaction, error) {
+	var t models.SentTransaction
+	err := d.Where("tx_hash = ?", txHash).First(&t).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询已发送交易失败（%s）: %w", txHash, err)
+	}
+	return &t, nil
+}
+
+// ListPendingSentTransactions 查询尚未进入最终状态的交易，按发送顺序
+func (d *DB) ListPendingSentTransactions() ([]*models.SentTransaction, error) {
+	var list []*models.SentTransaction
+	if err := d.Where("status = ?", models.TxStatusPending).Order("id").Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("查询待确认交易失败: %w", err)
+	}
+	return list, nil
+}
+
+// ListSentTransactionsByNonce 查询同一账户、同一 nonce 的所有交易（原交易及其加速、取消交易）
+func (d *DB) ListSentTransactionsByNonce(from string, nonce uint64) ([]*models.SentTransaction, error) {
+	var list []*models.SentTransaction
+	if err := d.Where(map[string]interface{}{"from": from, "nonce": nonce}).Order("id").Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("查询同一nonce的交易失败（%s:%d）: %w", from, nonce, err)
+	}
+	return list, nil
+}
+
+// SetSentTransactionBlock 记录交易所在区块（等待确认期间），链重组后交易不在链上时 blockNumber 为 0
+func (d *DB) SetSentTransactionBlock(t *models.SentTransaction, blockNumber uint64) error {
+	err := d.Model(t).Where("status = ?", models.TxStatusPending).Update("block_number", blockNumber).Error
+	if err != nil {
+		return fmt.Errorf("更新交易区块失败（%s）: %w", t.TxHash, err)
+	}
+	return nil
+}
+
+// ReplaceSentTransaction 交易已被节点丢弃，但同一 nonce 仍有待确认的加速或取消交易，标记为已替换
+func (d *DB) ReplaceSentTransaction(t *models.SentTransaction, replacedBy string) error {
+	err := d.Model(t).Where("status = ?", models.TxStatusPending).
+		Updates(map[string]interface{}{"status": models.TxStatusReplaced, "replaced_by": replacedBy}).Error
+	if err != nil {
+		return fmt.Errorf("更新交易状态失败（%s）: %w", t.TxHash, err)
+	}
+	return nil
+}
+
+// FinalizeSentTransaction 记录交易的最终状态（t 中的 Status、BlockNumber、GasUsed、RevertReason），并在同一事务中：
+//  1. 交易被打包时，同一 nonce 的其他待确认交易标记为已替换
+//  2. 打包成功的普通交易接替同一 nonce 各交易的空投分片和待确认 ERC20 记录，并重新匹配已索引的空投事件
+//  3. 执行失败、被丢弃或被取消时，同一 nonce 各交易对应的空投分片和待确认 ERC20 记录标记为失败
+//
+// 交易已被其他调用方处理（不再是待确认状态）时返回 false
+func (d *DB) FinalizeSentTransaction(t *models.SentTransaction) (bool, error) {
+	finalized := false
+	err := d.Transaction(func(tx *gorm.DB) error {
+		// 1. 更新交易状态
+		result := tx.Model(t).Where("status = ?", models.TxStatusPending).Updates(map[string]interface{}{
+			"status":        t.Status,
+			"block_number":  t.BlockNumber,
+			"gas_used":      t.GasUsed,
+			"revert_reason": t.RevertReason,
+		})
+		if result.Error != nil {
+			return fmt.Errorf("更新交易状态失败（%s）: %w", t.TxHash, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		finalized = true
+
+		// 2. 同一 nonce 的其他交易
+		var siblings []*models.SentTransaction
+		err := tx.Where(map[string]interface{}{"from": t.From, "nonce": t.Nonce}).Where("id <> ?", t.ID).Find(&siblings).Error
+		if err != nil {
+			return fmt.Errorf("查询同一nonce的交易失败（%s:%d）: %w", t.From, t.Nonce, err)
+		}
+		others := make([]string, len(siblings))
+		for i, s := range siblings {
+			others[i] = s.TxHash
+		}
+		if t.Status != models.TxStatusDropped && len(others) > 0 {
+			err = tx.Model(&models.SentTransaction{}).Where("tx_hash IN ? AND status = ?", others, models.TxStatusPending).
+				Updates(map[string]interface{}{"status": models.TxStatusReplaced, "replaced_by": t.TxHash}).Error
+			if err != nil {
+				return fmt.Errorf("更新被替换的交易失败（%s:%d）: %w", t.From, t.Nonce, err)
+			}
+		}
+
+		// 3. 更新关联的空投分片和 ERC20 记录
+		if t.Status == models.TxStatusMined && t.Kind != models.TxKindCancel {
+			if err := reassignTransactionRefs(tx, others, t.TxHash); err != nil {
+				return err
+			}
+			return rematchAirdropEvents(tx, t.TxHash)
+		}
+		return failTransactionRefs(tx, append(others, t.TxHash), sentTransactionFailure(t))
+	})
+	return finalized, err
+}
+
+// sentTransactionFailure 交易失败时记录到关联空投任务的原因
+func sentTransactionFailure(t *models.SentTransaction) string {
+	switch t.Status {
+	case models.TxStatusReverted:
+		return fmt.Sprintf("交易 %s 执行失败: %s", t.TxHash, t.RevertReason)
+	case models.TxStatusDropped:
+		return fmt.Sprintf("交易 %s 已被节点丢弃", t.TxHash)
+	default:
+		return fmt.Sprintf("交易已被取消（%s）", t.TxHash)
+	}
+}
+
+// reassignTransactionRefs 被替换的交易对应的空投分片、接收者和待确认 ERC20 记录改为指向实际打包的交易
+func reassignTransactionRefs(tx *gorm.DB, oldHashes []string, newHash string) error {
+	if len(oldHashes) == 0 {
+		return nil
+	}
+	if err := tx.Model(&models.AirdropJobChunk{}).Where("tx_hash IN ?", oldHashes).Update("tx_hash", newHash).Error; err != nil {
+		return fmt.Errorf("更新空投分片交易哈希失败: %w", err)
+	}
+	if err := tx.Model(&models.AirdropJobItem{}).Where("tx_hash IN ?", oldHashes).Update("tx_hash", newHash).Error; err != nil {
+		return fmt.Errorf("更新空投接收者交易哈希失败: %w", err)
+	}
+	err := tx.Model(&models.ERC20Transaction{}).Where("tx_hash IN ? AND pending = ?", oldHashes, true).Update("tx_hash", newHash).Error
+	if err != nil {
+		return fmt.Errorf("更新待确认ERC20交易哈希失败: %w", err)
+	}
+	return nil
+}
+
+// rematchAirdropEvents 重新匹配已索引的空投事件（事件可能先于交易状态更新被保存）
+func rematchAirdropEvents(tx *gorm.DB, txHash string) error {
+	var events []*models.AirdropEvent
+	err := tx.Where("transaction_hash = ?", common.HexToHash(txHash)).Order("log_index").Find(&events).Error
+	if err != nil {
+		return fmt.Errorf("查询空投事件失败（%s）: %w", txHash, err)
+	}
+	for _, event := range events {
+		if err := MatchAirdropEvent(tx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// failTransactionRefs 交易未能执行时，对应的空投分片标记为失败，删除待确认的 ERC20 记录
+func failTransactionRefs(tx *gorm.DB, hashes []string, message string) error {
+	var chunks []*models.AirdropJobChunk
+	if err := tx.Where("tx_hash IN ?", hashes).Find(&chunks).Error; err != nil {
+		return fmt.Errorf("查询空投分片失败: %w", err)
+	}
+	for _, chunk := range chunks {
+		if err := failAirdropChunk(tx, chunk, message); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.AirdropJob{}).Where("id = ?", chunk.JobID).Update("error", message).Error; err != nil {
+			return fmt.Errorf("更新空投任务失败（%d）: %w", chunk.JobID, err)
+		}
+	}
+	err := tx.Where("tx_hash IN ? AND pending = ?", hashes, true).Delete(&models.ERC20Transaction{}).Error
+	if err != nil {
+		return fmt.Errorf("删除待确认的ERC20交易记录失败: %w", err)
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"go-contracts/config"
 	"go-contracts/router"
 	"go-contracts/service"
+	"go-contracts/synchronizer/node"
 	"go-contracts/models"
 	"net/http"
 	"net/http/httptest"
@@ -23,13 +24,23 @@ func mockHandler(w http.ResponseWriter, r *http.Request) {
 type MockService struct{}
 
 // 实现AirdropBnb方法
-func (m *MockService) AirdropBnb(ctx context.Context, params service.AirdropParams) error {
-	return nil
+func (m *MockService) AirdropBnb(ctx context.Context, params service.AirdropParams) (*models.AirdropJob, error) {
+	return &models.AirdropJob{}, nil
 }
 
 // 实现AirdropERC20方法
-func (m *MockService) AirdropERC20(ctx context.Context, params service.AirdropParams) error {
-	return nil
+func (m *MockService) AirdropERC20(ctx context.Context, params service.AirdropParams) (*models.AirdropJob, error) {
+	return &models.AirdropJob{}, nil
+}
+
+// 实现GetAirdropJob方法
+func (m *MockService) GetAirdropJob(ctx context.Context, id uint) (*service.AirdropJobResult, error) {
+	return &service.AirdropJobResult{}, nil
+}
+
+// 实现AirdropStats方法
+func (m *MockService) AirdropStats(ctx context.Context) (*service.AirdropStats, error) {
+	return &service.AirdropStats{}, nil
 }
 
 // 实现AirdropSetGov方法
@@ -63,16 +74,16 @@ func (m *MockService) ERC20Allowance(ctx context.Context, params service.ERC20Al
 	return big.NewInt(0), nil
 }
 
-func (m *MockService) ERC20Approve(ctx context.Context, params service.ERC20ApproveParams) (*big.Int, error) {
-	return big.NewInt(0), nil
+func (m *MockService) ERC20Approve(ctx context.Context, params service.ERC20ApproveParams) (string, error) {
+	return "", nil
 }
 
-func (m *MockService) ERC20Transfer(ctx context.Context, params service.ERC20TransferParams) (*big.Int, error) {
-	return big.NewInt(0), nil
+func (m *MockService) ERC20Transfer(ctx context.Context, params service.ERC20TransferParams) (string, error) {
+	return "", nil
 }
 
-func (m *MockService) ERC20TransferFrom(ctx context.Context, params service.ERC20TransferFromParams) (*big.Int, error) {
-	return big.NewInt(0), nil
+func (m *MockService) ERC20TransferFrom(ctx context.Context, params service.ERC20TransferFromParams) (string, error) {
+	return "", nil
 }
 
 func (m *MockService) ERC20Balance(ctx context.Context, params service.ERC20BalanceParams) (*big.Int, error) {
@@ -87,6 +98,42 @@ func (m *MockService) ERC20TokenInfo(ctx context.Context, params service.ERC20Co
 	return &models.ERC20TokenInfo{}, nil
 }
 
+func (m *MockService) SimulateAirdropBnb(ctx context.Context, params service.AirdropParams) (*service.SimulationResult, error) {
+	return &service.SimulationResult{}, nil
+}
+
+func (m *MockService) SimulateAirdropERC20(ctx context.Context, params service.AirdropParams) (*service.SimulationResult, error) {
+	return &service.SimulationResult{}, nil
+}
+
+func (m *MockService) SimulateERC20Approve(ctx context.Context, params service.ERC20ApproveParams) (*service.SimulationResult, error) {
+	return &service.SimulationResult{}, nil
+}
+
+func (m *MockService) SimulateERC20Transfer(ctx context.Context, params service.ERC20TransferParams) (*service.SimulationResult, error) {
+	return &service.SimulationResult{}, nil
+}
+
+func (m *MockService) SimulateERC20TransferFrom(ctx context.Context, params service.ERC20TransferFromParams) (*service.SimulationResult, error) {
+	return &service.SimulationResult{}, nil
+}
+
+func (m *MockService) GetTransaction(ctx context.Context, txHash string) (*models.SentTransaction, error) {
+	return &models.SentTransaction{}, nil
+}
+
+func (m *MockService) SpeedUpTransaction(ctx context.Context, txHash string) (*models.SentTransaction, error) {
+	return &models.SentTransaction{}, nil
+}
+
+func (m *MockService) CancelTransaction(ctx context.Context, txHash string) (*models.SentTransaction, error) {
+	return &models.SentTransaction{}, nil
+}
+
+func (m *MockService) NodeStats(ctx context.Context) []node.EndpointStats {
+	return nil
+}
+
 func main() {
 	fmt.Println("===== 路由诊断工具 =====")
 
@@ -1,9 +1,15 @@
 package models
 
 import (
-	"time"
 	"github.com/ethereum/go-ethereum/common"
 	"gorm.io/gorm"
+	"time"
+)
+
+// 空投事件类型
+const (
+	AirdropTypeERC20 = "AirdropERC20"
+	AirdropTypeBNB   = "AirdropBNB"
 )
 
 // AirdropEvent 存储空投事件的数据模型
@@ -11,17 +17,20 @@ import (
 
 type AirdropEvent struct {
 	gorm.Model
-	
+
 	// 基础交易信息
-	TransactionHash common.Hash `gorm:"size:66;index" json:"transaction_hash"` // 交易哈希
-	BlockNumber     uint64       `gorm:"index" json:"block_number"`             // 区块号
-	BlockTime       time.Time    `json:"block_time"`                             // 区块时间
-	
+	TransactionHash common.Hash `gorm:"size:66;uniqueIndex:idx_tx_hash_log_index" json:"transaction_hash"` // 交易哈希
+	LogIndex        uint        `gorm:"uniqueIndex:idx_tx_hash_log_index" json:"log_index"`                // 日志在区块中的序号（同一交易的多个接收者按此区分）
+	BlockHash       common.Hash `gorm:"size:66" json:"block_hash"`                                         // 区块哈希
+	BlockNumber     uint64      `gorm:"index" json:"block_number"`                                         // 区块号
+	BlockTime       time.Time   `json:"block_time"`                                                        // 区块时间
+
 	// 事件特有信息
-	EventType       string       `gorm:"size:50;index" json:"event_type"`       // 事件类型：AirdropERC20 或 AirdropBNB
-	Recipient       common.Address `gorm:"size:42;index" json:"recipient"`      // 接收者地址
-	Amount          string       `gorm:"size:100" json:"amount"`                // 金额（以字符串形式存储大整数）
-	TokenAddress    common.Address `gorm:"size:42;index" json:"token_address"`  // 代币地址（BNB空投时为0x0000000000000000000000000000000000000000）
+	EventType       string         `gorm:"size:50;index" json:"event_type"`       // 事件类型：AirdropERC20 或 AirdropBNB
+	Recipient       common.Address `gorm:"size:42;index" json:"recipient"`        // 接收者地址
+	Amount          string         `gorm:"size:100" json:"amount"`                // 金额（以字符串形式存储大整数）
+	TokenAddress    common.Address `gorm:"size:42;index" json:"token_address"`    // 代币地址（BNB空投时为0x0000000000000000000000000000000000000000）
+	TokenResolved   bool           `gorm:"index" json:"token_resolved"`           // 代币地址是否已解析（查询失败时为 false，TokenAddress 无效）
 	ContractAddress common.Address `gorm:"size:42;index" json:"contract_address"` // 空投合约地址
 }
 
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+)
+
+// 空投任务状态
+const (
+	AirdropJobPending   = "pending"   // 已创建，交易尚未发送
+	AirdropJobSubmitted = "submitted" // 交易已发送，等待链上事件
+	AirdropJobCompleted = "completed" // 所有接收者均已到账
+	AirdropJobPartial   = "partial"   // 部分接收者到账，部分失败
+	AirdropJobFailed    = "failed"    // 全部失败
+)
+
+// 空投接收者状态
+const (
+	AirdropItemPending   = "pending"   // 交易尚未发送
+	AirdropItemSubmitted = "submitted" // 交易已发送
+	AirdropItemConfirmed = "confirmed" // 已匹配到链上空投事件
+	AirdropItemFailed    = "failed"    // 交易发送失败或执行失败
+)
+
+// AirdropJob 空投任务（一次空投请求）
+type AirdropJob struct {
+	ID              uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	EventType       string    `gorm:"size:50;index" json:"event_type"`       // 空投类型：AirdropERC20 或 AirdropBNB
+	ContractAddress string    `gorm:"size:42;index" json:"contract_address"` // 空投合约地址
+	TotalAmount     string    `gorm:"size:100" json:"total_amount"`          // 空投总金额
+	ItemCount       int       `json:"item_count"`                            // 接收者数量
+	ChunkCount      int       `json:"chunk_count"`                           // 分片（交易）数量
+	Status          string    `gorm:"size:20;index" json:"status"`           // 任务状态
+	Error           string    `gorm:"type:text" json:"error"`                // 失败原因
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// AirdropJobItem 空投任务中的单个接收者
+type AirdropJobItem struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	JobID       uint      `gorm:"index" json:"job_id"`            // 所属任务
+	ChunkID     uint      `gorm:"index" json:"chunk_id"`          // 所属分片
+	Recipient   string    `gorm:"size:42;index" json:"recipient"` // 接收者地址
+	Amount      string    `gorm:"size:100" json:"amount"`         // 空投金额
+	TxHash      string    `gorm:"size:66;index" json:"tx_hash"`   // 发送的交易哈希
+	Status      string    `gorm:"size:20;index" json:"status"`    // 接收者状态
+	BlockNumber uint64    `json:"block_number"`                   // 到账区块号（匹配到事件后填写）
+	LogIndex    *uint     `json:"log_index"`                      // 对应空投事件的日志索引（匹配到事件后填写）
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// AirdropJobChunk 空投任务的一个分片，对应一笔空投交易
+// 状态与任务状态取值相同，由分片内接收者的状态汇总得出
+type AirdropJobChunk struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	JobID       uint      `gorm:"uniqueIndex:idx_job_chunk" json:"job_id"`      // 所属任务
+	ChunkIndex  int       `gorm:"uniqueIndex:idx_job_chunk" json:"chunk_index"` // 分片序号（按发送顺序）
+	ItemCount   int       `json:"item_count"`                                   // 接收者数量
+	TotalAmount string    `gorm:"size:100" json:"total_amount"`                 // 分片总金额
+	GasLimit    uint64    `json:"gas_limit"`                                    // 交易 Gas 上限（发送前为切分时的预算）
+	Nonce       *uint64   `json:"nonce"`                                        // 交易 nonce（发送后填写）
+	TxHash      string    `gorm:"size:66;index" json:"tx_hash"`                 // 交易哈希
+	Status      string    `gorm:"size:20;index" json:"status"`                  // 分片状态
+	Error       string    `gorm:"type:text" json:"error"`                       // 失败原因
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName 自定义表名
+func (AirdropJob) TableName() string {
+	return "airdrop_jobs"
+}
+
+// TableName 自定义表名
+func (AirdropJobItem) TableName() string {
+	return "airdrop_job_items"
+}
+
+// TableName 自定义表名
+func (AirdropJobChunk) TableName() string {
+	return "airdrop_job_chunks"
+}
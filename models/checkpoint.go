@@ -0,0 +1,34 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 同步检查点名称
+const (
+	CheckpointIndexer       = "indexer"  // 区块索引服务
+	CheckpointAirdropPrefix = "airdrop:" // 空投事件监听（后接合约地址）
+)
+
+// AirdropCheckpointName 空投合约对应的检查点名称
+func AirdropCheckpointName(contract common.Address) string {
+	return CheckpointAirdropPrefix + strings.ToLower(contract.Hex())
+}
+
+// SyncCheckpoint 记录各同步任务已提交的最后区块号
+// 服务重启后从检查点的下一个区块继续同步
+type SyncCheckpoint struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string    `gorm:"size:100;uniqueIndex" json:"name"` // 检查点名称（同步任务标识）
+	BlockNumber uint64    `json:"block_number"`                     // 已提交的最后区块号
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName 自定义表名
+func (SyncCheckpoint) TableName() string {
+	return "sync_checkpoints"
+}
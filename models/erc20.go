@@ -4,6 +4,13 @@ import (
 	"time"
 )
 
+// ERC20 交易类型
+const (
+	ERC20TypeTransfer     = "transfer"
+	ERC20TypeTransferFrom = "transfer_from"
+	ERC20TypeApprove      = "approve"
+)
+
 // ERC20TokenInfo 表示ERC20代币的基本信息
 type ERC20TokenInfo struct {
 	ID          uint           `gorm:"primaryKey;autoIncrement" json:"id"`
@@ -18,28 +25,31 @@ type ERC20TokenInfo struct {
 
 // ERC20Transaction 表示ERC20代币交易记录
 type ERC20Transaction struct {
-	ID              uint           `gorm:"primaryKey;autoIncrement" json:"id"`
-	TxHash          string         `gorm:"size:66;index" json:"tx_hash"`      // 交易哈希
-	BlockHash       string         `gorm:"size:66;index" json:"block_hash"`   // 区块哈希
-	BlockNumber     uint64         `gorm:"index" json:"block_number"`          // 区块号
-	From            string         `gorm:"size:42;index" json:"from"`          // 发送方地址
-	To              string         `gorm:"size:42;index" json:"to"`            // 接收方地址
-	ContractAddress string         `gorm:"size:42;index" json:"contract_address"` // 合约地址
-	Amount          string         `gorm:"type:text" json:"amount"`           // 交易金额
-	GasUsed         uint64         `json:"gas_used"`                            // 消耗的Gas
-	GasPrice        string         `gorm:"type:text" json:"gas_price"`        // Gas价格
-	Status          bool           `json:"status"`                              // 交易状态
-	TransactionType string         `gorm:"size:50" json:"transaction_type"`   // 交易类型：transfer, transfer_from, approve
-	CreatedAt       time.Time      `json:"created_at"`
+	ID              uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	TxHash          string    `gorm:"size:66;uniqueIndex:idx_tx_hash_log_index" json:"tx_hash"` // 交易哈希
+	LogIndex        uint      `gorm:"uniqueIndex:idx_tx_hash_log_index" json:"log_index"`       // 日志在区块中的序号
+	BlockHash       string    `gorm:"size:66;index" json:"block_hash"`                          // 区块哈希
+	BlockNumber     uint64    `gorm:"index" json:"block_number"`                                // 区块号
+	From            string    `gorm:"size:42;index" json:"from"`                                // 发送方地址
+	To              string    `gorm:"size:42;index" json:"to"`                                  // 接收方地址
+	ContractAddress string    `gorm:"size:42;index" json:"contract_address"`                    // 合约地址
+	Amount          string    `gorm:"type:text" json:"amount"`                                  // 交易金额
+	GasUsed         uint64    `json:"gas_used"`                                                 // 消耗的Gas
+	GasPrice        string    `gorm:"type:text" json:"gas_price"`                               // Gas价格
+	Status          bool      `json:"status"`                                                   // 交易状态
+	TransactionType string    `gorm:"size:50" json:"transaction_type"`                          // 交易类型：transfer, transfer_from, approve
+	Pending         bool      `gorm:"index" json:"pending"`                                     // 本服务已发送、尚未被索引的交易（索引到链上日志后被替换）
+	CreatedAt       time.Time `json:"created_at"`
 }
 
 // ERC20Balance 表示用户的ERC20代币余额
 type ERC20Balance struct {
-	ID              uint           `gorm:"primaryKey;autoIncrement" json:"id"`
-	ContractAddress string         `gorm:"size:42;index:idx_contract_address_account" json:"contract_address"` // 合约地址
-	Account         string         `gorm:"size:42;index:idx_contract_address_account" json:"account"`         // 用户地址
-	Balance         string         `gorm:"type:text" json:"balance"`           // 余额
-	UpdatedAt       time.Time      `json:"updated_at"`
+	ID              uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	ContractAddress string    `gorm:"size:42;uniqueIndex:idx_contract_address_account" json:"contract_address"` // 合约地址
+	Account         string    `gorm:"size:42;uniqueIndex:idx_contract_address_account" json:"account"`          // 用户地址
+	Balance         string    `gorm:"type:text" json:"balance"`                                                 // 余额
+	BlockNumber     uint64    `json:"block_number"`                                                             // 最后一次变动所在区块号
+	UpdatedAt       time.Time `json:"updated_at"`
 }
 
 // TableName 设置表名
@@ -53,4 +63,4 @@ func (ERC20Transaction) TableName() string {
 
 func (ERC20Balance) TableName() string {
 	return "erc20_balances"
-}
\ No newline at end of file
+}
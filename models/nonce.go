@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// NonceReservation 已分配给交易、尚未被节点确认的 nonce（在途 nonce）
+// 服务重启后据此避免重复使用已经发出的 nonce
+type NonceReservation struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Address   string    `gorm:"size:42;uniqueIndex:idx_address_nonce" json:"address"` // 签名账户地址
+	Nonce     uint64    `gorm:"uniqueIndex:idx_address_nonce" json:"nonce"`           // 分配的 nonce
+	TxHash    string    `gorm:"size:66" json:"tx_hash"`                               // 发送成功后的交易哈希，为空表示尚未发送
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 自定义表名
+func (NonceReservation) TableName() string {
+	return "nonce_reservations"
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+)
+
+// 已发送交易的类型
+const (
+	TxKindAirdropBNB        = "airdrop_bnb"
+	TxKindAirdropERC20      = "airdrop_erc20"
+	TxKindSetGov            = "set_gov"
+	TxKindERC20Approve      = "erc20_approve"
+	TxKindERC20Transfer     = "erc20_transfer"
+	TxKindERC20TransferFrom = "erc20_transfer_from"
+	TxKindCancel            = "cancel" // 取消交易：同一 nonce 的 0 金额转给自己
+)
+
+// 已发送交易的状态
+const (
+	TxStatusPending  = "pending"  // 等待打包或确认
+	TxStatusMined    = "mined"    // 已打包并达到确认数，执行成功
+	TxStatusReverted = "reverted" // 已打包并达到确认数，执行失败
+	TxStatusDropped  = "dropped"  // 节点已丢弃，nonce 未被同组交易使用
+	TxStatusReplaced = "replaced" // 被同一 nonce 的加速或取消交易替换
+)
+
+// SentTransaction 本服务签名发送的交易
+// 保存完整的交易内容，加速（相同 nonce、更高费用）或取消时据此重新签名
+type SentTransaction struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	TxHash       string    `gorm:"size:66;uniqueIndex" json:"tx_hash"`       // 交易哈希
+	Kind         string    `gorm:"size:30" json:"kind"`                      // 交易类型
+	From         string    `gorm:"size:42;index:idx_from_nonce" json:"from"` // 签名账户
+	Nonce        uint64    `gorm:"index:idx_from_nonce" json:"nonce"`        // 交易 nonce
+	To           string    `gorm:"size:42" json:"to"`                        // 接收地址（合约地址）
+	Value        string    `gorm:"size:100" json:"value"`                    // 转账金额（wei）
+	Data         string    `gorm:"type:text" json:"data"`                    // 调用数据（十六进制）
+	GasLimit     uint64    `json:"gas_limit"`                                // Gas 上限
+	GasPrice     string    `gorm:"size:100" json:"gas_price"`                // 传统交易的 Gas 价格
+	GasFeeCap    string    `gorm:"size:100" json:"gas_fee_cap"`              // EIP-1559 maxFeePerGas
+	GasTipCap    string    `gorm:"size:100" json:"gas_tip_cap"`              // EIP-1559 maxPriorityFeePerGas
+	Status       string    `gorm:"size:20;index" json:"status"`              // 交易状态
+	BlockNumber  uint64    `json:"block_number"`                             // 打包区块（未打包为 0）
+	GasUsed      uint64    `json:"gas_used"`                                 // 实际消耗的 Gas
+	RevertReason string    `gorm:"type:text" json:"revert_reason"`           // 执行失败原因（解码后的 revert 信息）
+	Replaces     string    `gorm:"size:66" json:"replaces"`                  // 被本交易替换的交易哈希（加速或取消时填写）
+	ReplacedBy   string    `gorm:"size:66" json:"replaced_by"`               // 替换本交易并被打包的交易哈希
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TableName 自定义表名
+func (SentTransaction) TableName() string {
+	return "sent_transactions"
+}
+
+// Final 交易是否已进入最终状态，不再需要跟踪
+func (t *SentTransaction) Final() bool {
+	return t.Status != TxStatusPending
+}
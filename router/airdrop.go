@@ -2,10 +2,14 @@ package router
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"go-contracts/service"
 	"go-contracts/util"
 	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
 )
 
 // AirdropBnb 处理BNB空投请求
@@ -25,19 +29,37 @@ func (h Routes) AirdropBnb(w http.ResponseWriter, r *http.Request) {
 	// 3. 创建上下文
 	ctx := r.Context()
 
-	// 4. 调用服务层的AirdropBnb方法
-	if err := h.svc.AirdropBnb(ctx, params); err != nil {
+	// 4. dry_run 时只模拟执行，不发送交易
+	if params.DryRun {
+		result, err := h.svc.SimulateAirdropBnb(ctx, params)
+		if err != nil {
+			util.Log.Error("BNB空投模拟执行失败", "error", err)
+			h.handleError(w, http.StatusInternalServerError, "模拟空投失败: %v", err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code":    200,
+			"message": "BNB空投模拟执行完成，未发送交易",
+			"data":    result,
+		})
+		return
+	}
+
+	// 5. 调用服务层的AirdropBnb方法
+	job, err := h.svc.AirdropBnb(ctx, params)
+	if err != nil {
 		util.Log.Error("BNB空投失败", "error", err)
 		h.handleError(w, http.StatusInternalServerError, "执行空投失败: %v", err)
 		return
 	}
 
-	// 5. 返回成功响应
+	// 6. 返回成功响应
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"code":    200,
 		"message": "BNB空投请求已提交成功",
-		"data":    nil,
+		"data":    job,
 	})
 }
 
@@ -67,19 +89,37 @@ func (h Routes) AirdropERC20(w http.ResponseWriter, r *http.Request) {
 	// 3. 创建上下文
 	ctx := r.Context()
 
-	// 4. 调用服务层的AirdropERC20方法
-	if err := h.svc.AirdropERC20(ctx, params); err != nil {
+	// 4. dry_run 时只模拟执行，不发送交易
+	if params.DryRun {
+		result, err := h.svc.SimulateAirdropERC20(ctx, params)
+		if err != nil {
+			util.Log.Error("ERC20空投模拟执行失败", "error", err)
+			h.handleError(w, http.StatusInternalServerError, "模拟空投失败: %v", err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code":    200,
+			"message": "ERC20空投模拟执行完成，未发送交易",
+			"data":    result,
+		})
+		return
+	}
+
+	// 5. 调用服务层的AirdropERC20方法
+	job, err := h.svc.AirdropERC20(ctx, params)
+	if err != nil {
 		util.Log.Error("ERC20空投失败", "error", err)
 		h.handleError(w, http.StatusInternalServerError, "执行空投失败: %v", err)
 		return
 	}
 
-	// 5. 返回成功响应
+	// 6. 返回成功响应
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"code":    200,
 		"message": "ERC20空投请求已提交成功",
-		"data":    nil,
+		"data":    job,
 	})
 }
 
@@ -139,3 +179,58 @@ func (h Routes) AirdropGov(w http.ResponseWriter, r *http.Request) {
 		"data":    map[string]string{"gov_address": govAddress},
 	})
 }
+
+// GetAirdropJob 处理查询空投任务状态请求
+func (h Routes) GetAirdropJob(w http.ResponseWriter, r *http.Request) {
+	// 1. 设置响应头
+	w.Header().Set("Content-Type", "application/json")
+
+	// 2. 解析任务ID
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		h.handleError(w, http.StatusBadRequest, "无效的任务ID: %s", chi.URLParam(r, "id"))
+		return
+	}
+
+	// 3. 调用服务层的GetAirdropJob方法
+	result, err := h.svc.GetAirdropJob(r.Context(), uint(id))
+	if errors.Is(err, service.ErrAirdropJobNotFound) {
+		h.handleError(w, http.StatusNotFound, "空投任务不存在: %d", id)
+		return
+	}
+	if err != nil {
+		util.Log.Error("查询空投任务失败", "id", id, "error", err)
+		h.handleError(w, http.StatusInternalServerError, "查询空投任务失败: %v", err)
+		return
+	}
+
+	// 4. 返回成功响应
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":    200,
+		"message": "查询空投任务成功",
+		"data":    result,
+	})
+}
+
+// AirdropStats 处理空投事件统计查询请求
+func (h Routes) AirdropStats(w http.ResponseWriter, r *http.Request) {
+	// 1. 设置响应头
+	w.Header().Set("Content-Type", "application/json")
+
+	// 2. 调用服务层的AirdropStats方法
+	stats, err := h.svc.AirdropStats(r.Context())
+	if err != nil {
+		util.Log.Error("查询空投事件统计失败", "error", err)
+		h.handleError(w, http.StatusInternalServerError, "查询空投事件统计失败: %v", err)
+		return
+	}
+
+	// 3. 返回成功响应
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":    200,
+		"message": "查询空投事件统计成功",
+		"data":    stats,
+	})
+}
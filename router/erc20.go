@@ -32,13 +32,24 @@ func (h Routes) ERC20Approve(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// dry_run 时只模拟执行，不发送交易
+	if params.DryRun {
+		simulation, err := h.svc.SimulateERC20Approve(r.Context(), params)
+		if err != nil {
+			handlerError(w, err)
+			return
+		}
+		handlerSuccess(w, simulation)
+		return
+	}
+
 	result, err := h.svc.ERC20Approve(r.Context(), params)
 	if err != nil {
 		handlerError(w, err)
 		return
 	}
 
-	handlerSuccess(w, result.String())
+	handlerSuccess(w, result)
 }
 
 // ERC20Transfer 处理ERC20转账请求
@@ -49,13 +60,24 @@ func (h Routes) ERC20Transfer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// dry_run 时只模拟执行，不发送交易
+	if params.DryRun {
+		simulation, err := h.svc.SimulateERC20Transfer(r.Context(), params)
+		if err != nil {
+			handlerError(w, err)
+			return
+		}
+		handlerSuccess(w, simulation)
+		return
+	}
+
 	result, err := h.svc.ERC20Transfer(r.Context(), params)
 	if err != nil {
 		handlerError(w, err)
 		return
 	}
 
-	handlerSuccess(w, result.String())
+	handlerSuccess(w, result)
 }
 
 // ERC20TransferFrom 处理ERC20授权转账请求
@@ -66,13 +88,24 @@ func (h Routes) ERC20TransferFrom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// dry_run 时只模拟执行，不发送交易
+	if params.DryRun {
+		simulation, err := h.svc.SimulateERC20TransferFrom(r.Context(), params)
+		if err != nil {
+			handlerError(w, err)
+			return
+		}
+		handlerSuccess(w, simulation)
+		return
+	}
+
 	result, err := h.svc.ERC20TransferFrom(r.Context(), params)
 	if err != nil {
 		handlerError(w, err)
 		return
 	}
 
-	handlerSuccess(w, result.String())
+	handlerSuccess(w, result)
 }
 
 // ERC20Balance 处理ERC20余额查询请求
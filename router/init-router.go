@@ -23,11 +23,21 @@ const (
 	ERC20_TOTAL_SUPPLY  = "/api/erc20/total_supply"
 	ERC20_TOKEN_INFO    = "/api/erc20/token_info"
 
+	// 节点相关路由
+	NODE_STATS = "/api/node/stats"
+
 	// 空投相关路由
 	AIRDROP_SET_GOV = "/api/airdrop_set_gov"
 	AIRDROP_GOV     = "/api/airdrop_gov"
 	AIRDROP_BNB     = "/api/airdrop_bnb"
 	AIRDROP_ERC20   = "/api/airdrop_erc20"
+	AIRDROP_JOB     = "/api/airdrop/jobs/{id}"
+	AIRDROP_STATS   = "/api/airdrop/stats"
+
+	// 已发送交易相关路由
+	TX_STATUS   = "/api/tx/{hash}"
+	TX_SPEED_UP = "/api/tx/{hash}/speed_up"
+	TX_CANCEL   = "/api/tx/{hash}/cancel"
 )
 
 func InitRouter(conf config.HTTPServerConfig, cfg *config.Config, svc service.Service) *chi.Mux {
@@ -56,11 +66,21 @@ func InitRouter(conf config.HTTPServerConfig, cfg *config.Config, svc service.Se
 		w.Write([]byte(`{"status":"success","message":"API服务正常运行","timestamp":"` + time.Now().Format(time.RFC3339) + `"}`))
 	})
 
+	// 注册节点相关路由
+	router.Get(NODE_STATS, h.NodeStats) // 查询节点连接池统计
+
 	// 注册空投相关路由
 	router.Post(AIRDROP_SET_GOV, h.AirdropSetGov) // 设置空投合约地址
 	router.Get(AIRDROP_GOV, h.AirdropGov)         // 查询空投合约地址
-	router.Get(AIRDROP_BNB, h.AirdropBnb)      // BNB空投
-	router.Post(AIRDROP_ERC20, h.AirdropERC20) // ERC20空投
+	router.Get(AIRDROP_BNB, h.AirdropBnb)         // BNB空投
+	router.Post(AIRDROP_ERC20, h.AirdropERC20)    // ERC20空投
+	router.Get(AIRDROP_JOB, h.GetAirdropJob)      // 查询空投任务状态
+	router.Get(AIRDROP_STATS, h.AirdropStats)     // 查询空投事件统计（代币未解析的事件数）
+
+	// 注册已发送交易相关路由
+	router.Get(TX_STATUS, h.GetTransaction)        // 查询交易状态
+	router.Post(TX_SPEED_UP, h.SpeedUpTransaction) // 加速交易
+	router.Post(TX_CANCEL, h.CancelTransaction)    // 取消交易
 
 	// 注册ERC20相关路由
 	router.Post(ERC20_ALLOWANCE, h.ERC20Allowance)        // 查询授权
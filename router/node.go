@@ -0,0 +1,10 @@
+package router
+
+import (
+	"net/http"
+)
+
+// NodeStats 处理节点连接池统计查询请求
+func (h Routes) NodeStats(w http.ResponseWriter, r *http.Request) {
+	handlerSuccess(w, h.svc.NodeStats(r.Context()))
+}
@@ -0,0 +1,58 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-contracts/models"
+	"go-contracts/service"
+	"go-contracts/util"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GetTransaction 处理查询已发送交易状态请求
+func (h Routes) GetTransaction(w http.ResponseWriter, r *http.Request) {
+	h.handleTransaction(w, r, "查询交易状态", h.svc.GetTransaction)
+}
+
+// SpeedUpTransaction 处理加速交易请求（相同 nonce、更高费用）
+func (h Routes) SpeedUpTransaction(w http.ResponseWriter, r *http.Request) {
+	h.handleTransaction(w, r, "加速交易", h.svc.SpeedUpTransaction)
+}
+
+// CancelTransaction 处理取消交易请求（相同 nonce 的 0 金额自转账）
+func (h Routes) CancelTransaction(w http.ResponseWriter, r *http.Request) {
+	h.handleTransaction(w, r, "取消交易", h.svc.CancelTransaction)
+}
+
+// handleTransaction 以路径中的交易哈希调用服务层方法，返回交易记录
+func (h Routes) handleTransaction(w http.ResponseWriter, r *http.Request, action string, fn func(ctx context.Context, txHash string) (*models.SentTransaction, error)) {
+	// 1. 设置响应头
+	w.Header().Set("Content-Type", "application/json")
+
+	// 2. 调用服务层方法
+	txHash := chi.URLParam(r, "hash")
+	result, err := fn(r.Context(), txHash)
+	switch {
+	case errors.Is(err, service.ErrTransactionNotFound):
+		h.handleError(w, http.StatusNotFound, "交易不存在: %s", txHash)
+		return
+	case errors.Is(err, service.ErrTransactionNotPending):
+		h.handleError(w, http.StatusConflict, "%s失败: %v", action, err)
+		return
+	case err != nil:
+		util.Log.Error(action+"失败", "txHash", txHash, "error", err)
+		h.handleError(w, http.StatusInternalServerError, "%s失败: %v", action, err)
+		return
+	}
+
+	// 3. 返回成功响应
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":    200,
+		"message": action + "成功",
+		"data":    result,
+	})
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-contracts/database"
+	"go-contracts/models"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// defaultAirdropRangeSize 回填时每次查询日志的默认区块数
+const defaultAirdropRangeSize = 2000
+
+// loadNextBlock 计算下一个待处理的区块号：有检查点时从检查点之后开始，否则从配置的部署区块开始
+func (d *airdropDeployment) loadNextBlock() (uint64, error) {
+	checkpoint, err := d.db.GetCheckpoint(d.checkpointName)
+	if err != nil {
+		return 0, err
+	}
+	if checkpoint != nil {
+		return checkpoint.BlockNumber + 1, nil
+	}
+	return d.startBlock, nil
+}
+
+// saveCheckpoint 记录 [.., blockNumber] 的事件均已保存，下次启动从 blockNumber + 1 开始
+func (d *airdropDeployment) saveCheckpoint(blockNumber uint64) error {
+	if blockNumber+1 <= d.nextBlock.Load() {
+		return nil
+	}
+	if err := database.SaveCheckpoint(d.db.DB, d.checkpointName, blockNumber); err != nil {
+		return err
+	}
+	d.nextBlock.Store(blockNumber + 1)
+	return nil
+}
+
+// rewindIfNeeded 索引服务因链重组回退了检查点（并删除了孤块上的空投事件）时，将待处理位置回退到检查点之后
+func (d *airdropDeployment) rewindIfNeeded() (bool, error) {
+	checkpoint, err := d.db.GetCheckpoint(d.checkpointName)
+	if err != nil || checkpoint == nil || checkpoint.BlockNumber+1 >= d.nextBlock.Load() {
+		return false, err
+	}
+	d.log.Warn("空投检查点已被回退，重新回填", "from", checkpoint.BlockNumber+1, "previous", d.nextBlock.Load())
+	d.nextBlock.Store(checkpoint.BlockNumber + 1)
+	return true, nil
+}
+
+// backfill 从检查点回填到 target 的历史事件
+// 已达到确认数的区块直接保存并推进检查点，未达到确认数的事件交由确认队列处理
+func (d *airdropDeployment) backfill(ctx context.Context, target uint64) error {
+	d.scanMu.Lock()
+	defer d.scanMu.Unlock()
+
+	from := d.nextBlock.Load()
+	if from > target {
+		return nil
+	}
+	safe := uint64(0)
+	if target >= d.confirmations {
+		safe = target - d.confirmations
+	}
+	d.log.Info("开始回填空投历史事件", "from", from, "to", target, "confirmed", safe)
+	if err := d.scanRange(ctx, target, safe); err != nil {
+		return err
+	}
+	d.log.Info("空投历史事件回填完成", "to", target)
+	return nil
+}
+
+// scanRange 按 rangeSize 分段查询检查点之后到 target 的事件
+// safe 及以下的事件直接保存并推进检查点；高于 safe 的事件在订阅模式下加入确认队列，轮询模式下留待之后重新扫描
+func (d *airdropDeployment) scanRange(ctx context.Context, target, safe uint64) error {
+	for start := d.nextBlock.Load(); start <= target; start += d.rangeSize {
+		end := start + d.rangeSize - 1
+		if end > target {
+			end = target
+		}
+
+		events, err := d.filterEvents(ctx, start, end)
+		if err != nil {
+			return err
+		}
+		for _, p := range events {
+			if p.raw.BlockNumber > safe {
+				if !d.polling {
+					d.addPending(p)
+				}
+				continue
+			}
+			if err := d.handleAirdropEvent(ctx, p.eventType, p.event); err != nil {
+				return err
+			}
+		}
+
+		if start <= safe {
+			if err := d.saveCheckpoint(min(end, safe)); err != nil {
+				return err
+			}
+		}
+		if end < target {
+			d.log.Info("空投事件回填进度", "start", start, "end", end, "target", target, "events", len(events))
+		}
+	}
+	return nil
+}
+
+// filterEvents 查询 [start, end] 范围内的 AirdropERC20 与 AirdropBNB 事件，按区块和日志顺序排列
+func (d *airdropDeployment) filterEvents(ctx context.Context, start, end uint64) ([]*pendingAirdropEvent, error) {
+	opts := &bind.FilterOpts{Start: start, End: &end, Context: ctx}
+	var events []*pendingAirdropEvent
+
+	erc20Iter, err := d.contract.FilterAirdropERC20(opts, nil)
+	if err != nil {
+		return nil, fmt.Errorf("查询AirdropERC20事件失败（%d-%d）: %w", start, end, err)
+	}
+	for erc20Iter.Next() {
+		e := erc20Iter.Event
+		events = append(events, &pendingAirdropEvent{eventType: models.AirdropTypeERC20, event: e, raw: e.Raw})
+	}
+	err = erc20Iter.Error()
+	erc20Iter.Close()
+	if err != nil {
+		return nil, fmt.Errorf("遍历AirdropERC20事件失败（%d-%d）: %w", start, end, err)
+	}
+
+	bnbIter, err := d.contract.FilterAirdropBNB(opts, nil)
+	if err != nil {
+		return nil, fmt.Errorf("查询AirdropBNB事件失败（%d-%d）: %w", start, end, err)
+	}
+	for bnbIter.Next() {
+		e := bnbIter.Event
+		events = append(events, &pendingAirdropEvent{eventType: models.AirdropTypeBNB, event: e, raw: e.Raw})
+	}
+	err = bnbIter.Error()
+	bnbIter.Close()
+	if err != nil {
+		return nil, fmt.Errorf("遍历AirdropBNB事件失败（%d-%d）: %w", start, end, err)
+	}
+
+	sortEvents(events)
+	return events, nil
+}
+
+// sortEvents 按区块号、日志索引排序
+func sortEvents(events []*pendingAirdropEvent) {
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].raw.BlockNumber != events[j].raw.BlockNumber {
+			return events[i].raw.BlockNumber < events[j].raw.BlockNumber
+		}
+		return events[i].raw.Index < events[j].raw.Index
+	})
+}
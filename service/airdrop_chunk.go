@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-contracts/contract"
+	"go-contracts/models"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// airdropChunkGasShare 单笔空投交易最多占用区块 Gas 上限的 1/airdropChunkGasShare，给同区块其他交易留出空间
+	airdropChunkGasShare = 2
+	// airdropNewRecipientGas 新接收者（首次持有代币的地址或新账户）比已有接收者多出的开销（存储从零写入、新建账户）
+	airdropNewRecipientGas = 25000
+)
+
+// airdropMethods 空投类型对应的合约方法
+var airdropMethods = map[string]string{
+	models.AirdropTypeBNB:   "airdropBNB",
+	models.AirdropTypeERC20: "airdropERC20",
+}
+
+// airdropGas 空投交易的 Gas 模型：固定开销 + 每个接收者的开销，再加上安全余量
+// 只用于切分分片，发送前每个分片按实际接收者重新估算 Gas
+type airdropGas struct {
+	base          uint64
+	perRecipient  uint64
+	marginPercent uint64
+}
+
+// limit 返回 n 个接收者的交易 Gas 上限（含安全余量）
+func (g airdropGas) limit(n int) uint64 {
+	gas := g.base + g.perRecipient*uint64(n)
+	return gas + gas*g.marginPercent/100
+}
+
+// estimateAirdropGas 分别估算 1 个和 2 个接收者的 Gas，差值即为每个接收者的开销
+// 前两个接收者可能已持有代币，其余接收者按新接收者的最坏情况计算，保证分片实际估算的 Gas 不超过预算
+func (s *serviceImpl) estimateAirdropGas(ctx context.Context, from, contractAddress common.Address, eventType string, recipients []common.Address, amounts []*big.Int) (airdropGas, error) {
+	single, err := s.estimateAirdropCall(ctx, from, contractAddress, eventType, recipients[:1], amounts[:1])
+	if err != nil {
+		return airdropGas{}, err
+	}
+	var double uint64
+	if len(recipients) > 1 {
+		if double, err = s.estimateAirdropCall(ctx, from, contractAddress, eventType, recipients[:2], amounts[:2]); err != nil {
+			return airdropGas{}, err
+		}
+	}
+	return newAirdropGas(single, double, s.txCfg.GasMarginPercent), nil
+}
+
+// newAirdropGas 由 1 个和 2 个接收者的估算值（double 为 0 表示只有 1 个接收者）计算 Gas 模型
+// 第二个接收者的开销大于第一次调用的总开销时（如第一个接收者已持有代币、第二个是新账户），固定开销记为 0
+func newAirdropGas(single, double, marginPercent uint64) airdropGas {
+	gas := airdropGas{perRecipient: single, marginPercent: marginPercent}
+	if double > single {
+		per := double - single
+		gas.perRecipient = per
+		if per < single {
+			gas.base = single - per
+		}
+	}
+	gas.perRecipient += airdropNewRecipientGas
+	return gas
+}
+
+// estimateAirdropCall 估算一次空投调用的 Gas
+func (s *serviceImpl) estimateAirdropCall(ctx context.Context, from, contractAddress common.Address, eventType string, recipients []common.Address, amounts []*big.Int) (uint64, error) {
+	parsed, err := contract.AirdropMetaData.GetAbi()
+	if err != nil {
+		return 0, fmt.Errorf("解析空投合约ABI失败: %w", err)
+	}
+	data, err := parsed.Pack(airdropMethods[eventType], recipients, amounts)
+	if err != nil {
+		return 0, fmt.Errorf("编码空投调用失败: %w", err)
+	}
+
+	msg := ethereum.CallMsg{From: from, To: &contractAddress, Data: data}
+	if eventType == models.AirdropTypeBNB {
+		msg.Value = sumAmounts(amounts)
+	}
+	gas, err := s.ethClient.EstimateGas(ctx, msg)
+	if err != nil {
+		return 0, fmt.Errorf("估算空投Gas失败: %w", err)
+	}
+	return gas, nil
+}
+
+// planAirdropChunks 按 Gas 预算把 n 个接收者切分为若干分片，返回各分片的接收者数量
+func planAirdropChunks(n int, gas airdropGas, budget uint64) ([]int, error) {
+	size := 0
+	for size < n && gas.limit(size+1) <= budget {
+		size++
+	}
+	if size == 0 {
+		return nil, fmt.Errorf("单个接收者的空投Gas（%d）超过预算（%d）", gas.limit(1), budget)
+	}
+
+	sizes := make([]int, 0, (n+size-1)/size)
+	for remaining := n; remaining > 0; remaining -= size {
+		sizes = append(sizes, min(size, remaining))
+	}
+	return sizes, nil
+}
+
+// sumAmounts 计算金额总和
+func sumAmounts(amounts []*big.Int) *big.Int {
+	total := new(big.Int)
+	for _, amount := range amounts {
+		total.Add(total, amount)
+	}
+	return total
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAirdropGas(t *testing.T) {
+	tests := []struct {
+		name          string
+		single        uint64
+		double        uint64
+		base, perUser uint64
+	}{
+		{"只有一个接收者", 50000, 0, 0, 50000 + airdropNewRecipientGas},
+		{"固定开销加每个接收者的开销", 50000, 70000, 30000, 20000 + airdropNewRecipientGas},
+		{"第二个接收者是新账户", 30000, 90000, 0, 60000 + airdropNewRecipientGas},
+		{"两个接收者的估算值不大于一个", 50000, 50000, 0, 50000 + airdropNewRecipientGas},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gas := newAirdropGas(tt.single, tt.double, 20)
+			assert.Equal(t, tt.base, gas.base)
+			assert.Equal(t, tt.perUser, gas.perRecipient)
+			assert.Equal(t, uint64(20), gas.marginPercent)
+		})
+	}
+}
+
+func TestPlanAirdropChunks(t *testing.T) {
+	gas := airdropGas{base: 30000, perRecipient: 10000, marginPercent: 20}
+
+	tests := []struct {
+		name    string
+		n       int
+		budget  uint64
+		sizes   []int
+		wantErr bool
+	}{
+		// (30000 + 10000*80) * 1.2 = 996000，81 个接收者超出预算
+		{"均匀切分后剩余一个小分片", 200, 1000000, []int{80, 80, 40}, false},
+		{"恰好一个分片", 80, 1000000, []int{80}, false},
+		{"接收者少于分片上限", 5, 1000000, []int{5}, false},
+		{"单个接收者超出预算", 5, 40000, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sizes, err := planAirdropChunks(tt.n, gas, tt.budget)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.sizes, sizes)
+		})
+	}
+}
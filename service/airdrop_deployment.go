@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-contracts/config"
+	"go-contracts/contract"
+	"go-contracts/models"
+	"go-contracts/util"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// airdropDeployment 单个空投合约部署的监听状态
+// 每个合约有独立的检查点、确认队列和订阅，共用监听服务的数据库、节点连接和区块头缓存
+type airdropDeployment struct {
+	*AirdropWatcher
+
+	contract     *contract.Airdrop // 空投合约实例
+	contractAddr common.Address    // 空投合约地址
+	log          log.Logger        // 带合约地址的日志
+
+	pendingMu sync.Mutex                      // 保护 pending
+	pending   map[string]*pendingAirdropEvent // 等待确认的事件（交易哈希:日志索引）
+
+	startBlock     uint64        // 合约部署区块（无检查点时的回填起点）
+	checkpointName string        // 检查点名称
+	nextBlock      atomic.Uint64 // 下一个待处理的区块号（之前的事件均已保存）
+	backfillTarget atomic.Uint64 // 回填覆盖的最高区块，订阅推送的该高度及以下事件由回填处理
+	subsDown       atomic.Int32  // 尚未建立或中断后尚未补齐缺口的订阅数，大于 0 时检查点不前进
+	scanMu         sync.Mutex    // 串行化回填与检查点推进
+	tokens         tokenCache    // 代币地址缓存（按区块范围）
+}
+
+// newAirdropDeployment 根据配置创建空投合约的监听状态，并从检查点恢复回填起点
+func newAirdropDeployment(w *AirdropWatcher, cfg config.AirdropContractConfig) (*airdropDeployment, error) {
+	if !common.IsHexAddress(cfg.Address) {
+		return nil, fmt.Errorf("无效的空投合约地址: %s", cfg.Address)
+	}
+	contractAddr := common.HexToAddress(cfg.Address)
+	airdropContract, err := contract.NewAirdrop(contractAddr, w.ethClient)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &airdropDeployment{
+		AirdropWatcher: w,
+		contract:       airdropContract,
+		contractAddr:   contractAddr,
+		log:            util.Log.With("contract", contractAddr.Hex()),
+		pending:        make(map[string]*pendingAirdropEvent),
+		startBlock:     cfg.StartBlock,
+		checkpointName: models.AirdropCheckpointName(contractAddr),
+	}
+	next, err := d.loadNextBlock()
+	if err != nil {
+		return nil, err
+	}
+	d.nextBlock.Store(next)
+	return d, nil
+}
+
+// start 回填历史事件并开始监听（非阻塞）
+func (d *airdropDeployment) start(ctx context.Context) error {
+	d.log.Info("开始监听空投合约", "next_block", d.nextBlock.Load())
+	go d.tokenRetryLoop(ctx)
+
+	// 节点不支持订阅时，回填后改为定期轮询
+	if d.polling {
+		go func() {
+			if err := d.pollOnce(ctx); err != nil {
+				if ctx.Err() == nil {
+					d.log.Error("回填空投历史事件失败", "err", err)
+					d.shutdown(err)
+				}
+				return
+			}
+			d.pollLoop(ctx)
+		}()
+		return nil
+	}
+
+	// 先建立订阅再回填：两个订阅建立后各自查询链头并回填到链头（见 fillGap），
+	// 订阅建立前后之间的区块由回填覆盖，重叠的事件按 (交易哈希, 日志索引) 去重；回填完成前检查点不前进
+	d.subsDown.Store(2)
+	go d.superviseSubscription(ctx, models.AirdropTypeERC20, d.watchAirdropERC20)
+	go d.superviseSubscription(ctx, models.AirdropTypeBNB, d.watchAirdropBNB)
+
+	// 启动确认检查协程（保存待确认事件并推进检查点）
+	go d.confirmLoop(ctx)
+
+	return nil
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-contracts/models"
+	"go-contracts/util"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrAirdropJobNotFound 空投任务不存在
+var ErrAirdropJobNotFound = errors.New("空投任务不存在")
+
+// AirdropJobResult 空投任务及其分片、接收者状态
+type AirdropJobResult struct {
+	Job    *models.AirdropJob        `json:"job"`
+	Chunks []*models.AirdropJobChunk `json:"chunks"`
+	Items  []*models.AirdropJobItem  `json:"items"`
+}
+
+// airdropSendFunc 发送一个分片的空投交易（合约绑定的 AirdropBNB / AirdropERC20）
+type airdropSendFunc func(opts *bind.TransactOpts, recipients []common.Address, amounts []*big.Int) (*types.Transaction, error)
+
+// sendAirdrop 按 Gas 预算切分接收者，记录空投任务后依次发送各分片，每个分片发送前按实际接收者估算 Gas
+// 第一个分片发送失败时返回错误；之后的分片失败时任务部分完成，失败原因记录在任务和分片上
+func (s *serviceImpl) sendAirdrop(ctx context.Context, eventType string, auth *bind.TransactOpts, contractAddress common.Address, send airdropSendFunc, recipients []common.Address, amounts []*big.Int) (*models.AirdropJob, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("数据库未初始化")
+	}
+
+	// 1. 估算每个接收者的 Gas，按区块 Gas 上限切分
+	gas, err := s.estimateAirdropGas(ctx, auth.From, contractAddress, eventType, recipients, amounts)
+	if err != nil {
+		return nil, err
+	}
+	head, err := s.ethClient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("查询最新区块失败: %w", err)
+	}
+	sizes, err := planAirdropChunks(len(recipients), gas, head.GasLimit/airdropChunkGasShare)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 记录空投任务
+	job, chunks, err := s.createAirdropJob(eventType, contractAddress, sizes, gas, recipients, amounts)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 依次发送各分片（nonce 由分配器依次分配），某个分片失败后其余分片不再发送
+	kind := models.TxKindAirdropERC20
+	if eventType == models.AirdropTypeBNB {
+		kind = models.TxKindAirdropBNB
+	}
+	offset := 0
+	for i, chunk := range chunks {
+		lo, hi := offset, offset+chunk.ItemCount
+		offset = hi
+
+		opts := *auth
+		opts.Context = ctx
+		if eventType == models.AirdropTypeBNB {
+			opts.Value = sumAmounts(amounts[lo:hi])
+		}
+
+		tx, err := s.sendAirdropChunk(&opts, kind, eventType, contractAddress, send, recipients[lo:hi], amounts[lo:hi])
+		if err != nil {
+			err = fmt.Errorf("分片 %d/%d 发送失败: %w", i+1, len(chunks), err)
+			s.failAirdropChunks(job, chunks[i:], err)
+			if i == 0 {
+				return nil, fmt.Errorf("空投任务 %d 发送失败: %w", job.ID, err)
+			}
+			util.Log.Error("空投分片发送失败，任务部分完成", "job", job.ID, "chunk", i, "error", err)
+			break
+		}
+
+		util.Log.Info("空投分片已发送", "job", job.ID, "chunk", i, "recipients", chunk.ItemCount, "gas", tx.Gas(), "nonce", tx.Nonce(), "txHash", tx.Hash().Hex())
+		chunk.GasLimit = tx.Gas()
+		if err := s.db.SubmitAirdropChunk(chunk, tx.Hash().Hex(), tx.Nonce()); err != nil {
+			// 交易已发送，记录失败不影响空投本身，接收者仍可通过事件匹配
+			util.Log.Error("记录空投分片交易失败", "job", job.ID, "chunk", i, "txHash", tx.Hash().Hex(), "error", err)
+		}
+	}
+
+	// 4. 返回最新的任务状态
+	latest, _, _, err := s.db.GetAirdropJob(job.ID)
+	if err != nil || latest == nil {
+		return job, nil
+	}
+	return latest, nil
+}
+
+// sendAirdropChunk 估算分片的 Gas（加上安全余量）后发送
+func (s *serviceImpl) sendAirdropChunk(opts *bind.TransactOpts, kind, eventType string, contractAddress common.Address, send airdropSendFunc, recipients []common.Address, amounts []*big.Int) (*types.Transaction, error) {
+	estimated, err := s.estimateAirdropCall(opts.Context, opts.From, contractAddress, eventType, recipients, amounts)
+	if err != nil {
+		return nil, err
+	}
+	opts.GasLimit = s.withGasMargin(estimated)
+	return s.sendWithNonce(opts, kind, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return send(opts, recipients, amounts)
+	})
+}
+
+// parseAirdropParams 解析空投接收者地址和金额
+func parseAirdropParams(params AirdropParams) ([]common.Address, []*big.Int, error) {
+	if len(params.Recipients) == 0 || len(params.Amounts) == 0 {
+		return nil, nil, fmt.Errorf("接收者地址和金额不能为空")
+	}
+	if len(params.Recipients) != len(params.Amounts) {
+		return nil, nil, fmt.Errorf("接收者地址数量和金额数量不匹配")
+	}
+
+	recipients := make([]common.Address, len(params.Recipients))
+	for i, addrStr := range params.Recipients {
+		if !common.IsHexAddress(addrStr) {
+			return nil, nil, fmt.Errorf("无效的以太坊地址: %s", addrStr)
+		}
+		recipients[i] = common.HexToAddress(addrStr)
+	}
+	amounts := make([]*big.Int, len(params.Amounts))
+	for i, amountStr := range params.Amounts {
+		amount, ok := new(big.Int).SetString(amountStr, 10)
+		if !ok {
+			return nil, nil, fmt.Errorf("无效的金额格式: %s", amountStr)
+		}
+		amounts[i] = amount
+	}
+	return recipients, amounts, nil
+}
+
+// createAirdropJob 发送交易前记录空投任务及分片，所有接收者初始为待发送状态
+func (s *serviceImpl) createAirdropJob(eventType string, contractAddress common.Address, sizes []int, gas airdropGas, recipients []common.Address, amounts []*big.Int) (*models.AirdropJob, []*models.AirdropJobChunk, error) {
+	items := make([]*models.AirdropJobItem, len(recipients))
+	for i, recipient := range recipients {
+		items[i] = &models.AirdropJobItem{
+			Recipient: recipient.Hex(),
+			Amount:    amounts[i].String(),
+			Status:    models.AirdropItemPending,
+		}
+	}
+
+	chunks := make([]*models.AirdropJobChunk, len(sizes))
+	offset := 0
+	for i, size := range sizes {
+		chunks[i] = &models.AirdropJobChunk{
+			ChunkIndex:  i,
+			ItemCount:   size,
+			TotalAmount: sumAmounts(amounts[offset : offset+size]).String(),
+			GasLimit:    gas.limit(size),
+			Status:      models.AirdropJobPending,
+		}
+		offset += size
+	}
+
+	job := &models.AirdropJob{
+		EventType:       eventType,
+		ContractAddress: contractAddress.Hex(),
+		TotalAmount:     sumAmounts(amounts).String(),
+		ItemCount:       len(items),
+		ChunkCount:      len(chunks),
+		Status:          models.AirdropJobPending,
+	}
+	if err := s.db.CreateAirdropJob(job, chunks, items); err != nil {
+		return nil, nil, err
+	}
+	return job, chunks, nil
+}
+
+// failAirdropChunks 将未发送成功的分片标记为失败，并记录任务失败原因
+func (s *serviceImpl) failAirdropChunks(job *models.AirdropJob, chunks []*models.AirdropJobChunk, cause error) {
+	for i, chunk := range chunks {
+		message := cause.Error()
+		if i > 0 {
+			message = "前序分片发送失败，未发送"
+		}
+		if err := s.db.FailAirdropChunk(chunk, message); err != nil {
+			util.Log.Error("更新空投分片失败", "job", job.ID, "chunk", chunk.ChunkIndex, "error", err)
+		}
+	}
+	if err := s.db.SetAirdropJobError(job.ID, cause.Error()); err != nil {
+		util.Log.Error("更新空投任务失败", "job", job.ID, "error", err)
+	}
+}
+
+// GetAirdropJob 查询空投任务，已发送但执行失败（回执状态为失败）的分片对应的接收者标记为失败
+func (s *serviceImpl) GetAirdropJob(ctx context.Context, id uint) (*AirdropJobResult, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("数据库未初始化")
+	}
+
+	// 1. 查询任务、分片及接收者
+	job, chunks, items, err := s.db.GetAirdropJob(id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, ErrAirdropJobNotFound
+	}
+	if s.ethClient == nil {
+		return &AirdropJobResult{Job: job, Chunks: chunks, Items: items}, nil
+	}
+
+	// 2. 检查仍在等待事件的分片的交易回执，执行失败的交易不会产生空投事件
+	changed := false
+	for _, chunk := range chunks {
+		if chunk.Status != models.AirdropJobSubmitted || chunk.TxHash == "" {
+			continue
+		}
+		receipt, err := s.ethClient.TransactionReceipt(ctx, common.HexToHash(chunk.TxHash))
+		if err != nil {
+			continue // 交易尚未打包或节点暂不可用，下次查询再检查
+		}
+		if receipt.Status != types.ReceiptStatusFailed {
+			continue
+		}
+		message := fmt.Sprintf("分片 %d 交易执行失败: %s", chunk.ChunkIndex+1, chunk.TxHash)
+		if err := s.db.FailAirdropChunk(chunk, message); err != nil {
+			return nil, err
+		}
+		if err := s.db.SetAirdropJobError(id, message); err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+	if !changed {
+		return &AirdropJobResult{Job: job, Chunks: chunks, Items: items}, nil
+	}
+
+	// 3. 重新读取更新后的状态
+	job, chunks, items, err = s.db.GetAirdropJob(id)
+	if err != nil {
+		return nil, err
+	}
+	return &AirdropJobResult{Job: job, Chunks: chunks, Items: items}, nil
+}
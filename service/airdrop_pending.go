@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// confirmPollInterval 检查待确认空投事件的间隔
+const confirmPollInterval = 3 * time.Second
+
+// pendingAirdropEvent 等待达到确认数的空投事件
+type pendingAirdropEvent struct {
+	eventType string
+	event     interface{}
+	raw       types.Log
+}
+
+// eventKey 事件唯一标识（交易哈希 + 日志索引）
+func eventKey(raw types.Log) string {
+	return fmt.Sprintf("%s:%d", raw.TxHash.Hex(), raw.Index)
+}
+
+// enqueueEvent 接收订阅推送的事件
+// 回填终点及以下的事件由回填处理；未设置确认数时立即保存，否则暂存直到事件所在区块低于链头 N 个区块
+func (d *airdropDeployment) enqueueEvent(ctx context.Context, eventType string, event interface{}, raw types.Log) {
+	if raw.BlockNumber <= d.backfillTarget.Load() {
+		return
+	}
+
+	if d.confirmations == 0 && !raw.Removed {
+		err := d.handleAirdropEvent(ctx, eventType, event)
+		if err == nil {
+			return
+		}
+		d.log.Warn("保存空投事件失败，稍后重试", "type", eventType, "tx", raw.TxHash.Hex(), "err", err)
+	}
+
+	d.pendingMu.Lock()
+	defer d.pendingMu.Unlock()
+
+	key := eventKey(raw)
+	if raw.Removed {
+		// 事件所在区块已被重组移出主链
+		if _, ok := d.pending[key]; ok {
+			delete(d.pending, key)
+			d.log.Warn("空投事件因链重组被移除", "type", eventType, "tx", raw.TxHash.Hex(), "block", raw.BlockNumber)
+		}
+		return
+	}
+	d.pending[key] = &pendingAirdropEvent{eventType: eventType, event: event, raw: raw}
+	d.log.Debug("空投事件等待确认", "type", eventType, "tx", raw.TxHash.Hex(), "block", raw.BlockNumber)
+}
+
+// addPending 将事件加入确认队列
+func (d *airdropDeployment) addPending(p *pendingAirdropEvent) {
+	d.pendingMu.Lock()
+	d.pending[eventKey(p.raw)] = p
+	d.pendingMu.Unlock()
+}
+
+// confirmLoop 定期检查链头，保存已达到确认数的事件并推进检查点
+func (d *airdropDeployment) confirmLoop(ctx context.Context) {
+	ticker := time.NewTicker(confirmPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.flushConfirmed(ctx); err != nil {
+				d.log.Warn("检查空投事件确认数失败", "err", err)
+			}
+		}
+	}
+}
+
+// flushConfirmed 按区块顺序保存已达到确认数且仍在主链上的事件
+func (d *airdropDeployment) flushConfirmed(ctx context.Context) error {
+	head, err := d.ethClient.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if rewound, err := d.rewindIfNeeded(); err != nil {
+		return err
+	} else if rewound {
+		if err := d.backfill(ctx, head); err != nil {
+			return err
+		}
+	}
+
+	d.pendingMu.Lock()
+	var ready []*pendingAirdropEvent
+	for key, p := range d.pending {
+		if p.raw.BlockNumber+d.confirmations <= head {
+			ready = append(ready, p)
+			delete(d.pending, key)
+		}
+	}
+	d.pendingMu.Unlock()
+
+	sortEvents(ready)
+
+	for i, p := range ready {
+		// 再次确认事件所在区块仍是主链区块，避免遗漏的重组通知
+		header, err := d.ethClient.HeaderByNumber(ctx, new(big.Int).SetUint64(p.raw.BlockNumber))
+		if err != nil {
+			// 放回队列，下次重试
+			for _, rest := range ready[i:] {
+				d.addPending(rest)
+			}
+			return err
+		}
+		if header.Hash() != p.raw.BlockHash {
+			d.log.Warn("空投事件所在区块已不在主链上，丢弃", "type", p.eventType, "tx", p.raw.TxHash.Hex(), "block", p.raw.BlockNumber)
+			continue
+		}
+		if err := d.handleAirdropEvent(ctx, p.eventType, p.event); err != nil {
+			for _, rest := range ready[i:] {
+				d.addPending(rest)
+			}
+			return err
+		}
+	}
+	return d.advanceCheckpoint(head)
+}
+
+// advanceCheckpoint 将检查点推进到已确认且没有待处理事件的最高区块
+// 未设置确认数时保留一个区块的余量，避免订阅推送晚于链头查询导致遗漏
+// 订阅中断期间不推进，缺口由 fillGap 补齐
+func (d *airdropDeployment) advanceCheckpoint(head uint64) error {
+	// 有订阅中断且缺口尚未补齐时，检查点之后可能存在遗漏的事件
+	if d.subsDown.Load() > 0 {
+		return nil
+	}
+	d.scanMu.Lock()
+	defer d.scanMu.Unlock()
+
+	margin := d.confirmations
+	if margin == 0 {
+		margin = 1
+	}
+	if head < margin {
+		return nil
+	}
+	safe := head - margin
+
+	d.pendingMu.Lock()
+	for _, p := range d.pending {
+		if p.raw.BlockNumber <= safe {
+			if p.raw.BlockNumber == 0 {
+				d.pendingMu.Unlock()
+				return nil
+			}
+			safe = p.raw.BlockNumber - 1
+		}
+	}
+	d.pendingMu.Unlock()
+
+	return d.saveCheckpoint(safe)
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// defaultAirdropPollInterval 轮询模式下查询新事件的默认间隔
+const defaultAirdropPollInterval = 5 * time.Second
+
+// pollLoop 节点不支持订阅（如 HTTP 节点）时，定期以 eth_getLogs 查询检查点之后已确认的事件
+// 与回填共用检查点和去重逻辑，未确认的区块留待之后重新扫描，因此不需要确认队列
+func (d *airdropDeployment) pollLoop(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	d.log.Info("开始轮询空投事件", "interval", d.pollInterval)
+	for {
+		select {
+		case <-ctx.Done():
+			d.log.Info("空投事件轮询停止")
+			return
+		case <-ticker.C:
+			if err := d.pollOnce(ctx); err != nil && ctx.Err() == nil {
+				d.log.Warn("轮询空投事件失败，下次重试", "err", err)
+			}
+		}
+	}
+}
+
+// pollOnce 扫描检查点之后到最新已确认区块的事件
+func (d *airdropDeployment) pollOnce(ctx context.Context) error {
+	head, err := d.ethClient.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := d.rewindIfNeeded(); err != nil {
+		return err
+	}
+	if head < d.confirmations {
+		return nil
+	}
+	safe := head - d.confirmations
+	return d.scanRange(ctx, safe, safe)
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+const (
+	defaultMaxRestarts   = 10              // 订阅连续失败的默认最大重试次数
+	resubscribeBaseDelay = time.Second     // 重新订阅的初始退避时间
+	resubscribeMaxDelay  = time.Minute     // 重新订阅的最大退避时间
+	subscriptionStable   = 5 * time.Minute // 订阅持续超过该时间视为恢复稳定，重置重试计数
+)
+
+// superviseSubscription 运行订阅并在出错时以带抖动的指数退避重新订阅
+// 连续失败超过 maxRestarts 次时关闭服务；首次订阅建立及订阅恢复后通过 eth_getLogs 补齐检查点之后的区块
+// 调用方需预先将 subsDown 加 1（首次订阅建立前同样视为存在缺口）
+func (d *airdropDeployment) superviseSubscription(ctx context.Context, name string, watch func(ctx context.Context, subscribed func()) error) {
+	restarts := 0
+	delay := resubscribeBaseDelay
+	down := true
+
+	for {
+		var subscribedAt time.Time
+		err := watch(ctx, func() {
+			subscribedAt = time.Now()
+			if down {
+				down = false
+				go d.fillGap(ctx, name)
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		// 订阅中断：在缺口补齐前暂停推进检查点
+		if !down {
+			down = true
+			d.subsDown.Add(1)
+		}
+
+		// 订阅曾稳定运行一段时间，重新计算重试次数
+		if !subscribedAt.IsZero() && time.Since(subscribedAt) >= subscriptionStable {
+			restarts = 0
+			delay = resubscribeBaseDelay
+		}
+		restarts++
+		if restarts > d.maxRestarts {
+			d.log.Error("订阅连续失败次数超过上限，关闭服务", "event", name, "restarts", restarts-1, "err", err)
+			d.shutdown(fmt.Errorf("%s 订阅连续失败 %d 次: %w", name, restarts-1, err))
+			return
+		}
+
+		wait := jitter(delay)
+		d.log.Warn("订阅中断，等待后重新订阅", "event", name, "attempt", restarts, "wait", wait, "err", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		delay = min(delay*2, resubscribeMaxDelay)
+	}
+}
+
+// fillGap 订阅建立或恢复后，查询检查点之后到当前链头的事件，补齐启动前或订阅中断期间遗漏的区块
+func (d *airdropDeployment) fillGap(ctx context.Context, name string) {
+	head, err := d.ethClient.BlockNumber(ctx)
+	if err == nil {
+		// 订阅只推送建立之后的新区块，链头及以下的事件由补齐处理
+		if head > d.backfillTarget.Load() {
+			d.backfillTarget.Store(head)
+		}
+		d.log.Info("订阅已建立，补齐检查点之后的事件", "event", name, "from", d.nextBlock.Load(), "to", head)
+		err = d.backfill(ctx, head)
+	}
+	if err != nil {
+		if ctx.Err() == nil {
+			d.log.Error("补齐检查点之后的事件失败", "event", name, "err", err)
+			d.shutdown(err)
+		}
+		return
+	}
+	d.subsDown.Add(-1)
+}
+
+// jitter 在 [d/2, d) 之间随机取值，避免多个订阅同时重连
+func jitter(d time.Duration) time.Duration {
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
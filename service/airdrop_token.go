@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	tokenCacheSize     = 4096        // 代币地址缓存的最大范围数，超过后淘汰区块最早的范围
+	tokenRetryInterval = time.Minute // 重新解析代币地址的间隔
+	tokenRetryBatch    = 100         // 每次重新解析的最大事件数
+)
+
+// AirdropStats 空投事件统计
+type AirdropStats struct {
+	UnresolvedTokenEvents int64 `json:"unresolved_token_events"` // 代币地址未解析的 ERC20 空投事件数（大于 0 时需检查节点状态）
+}
+
+// tokenRange 区块范围 [from, to] 内空投合约的代币地址
+type tokenRange struct {
+	from, to uint64
+	token    common.Address
+}
+
+// tokenCache 按区块范围缓存空投合约的代币地址
+// 合约没有代币变更事件，两次查询之间代币可能变更后又改回，只合并相邻区块的查询结果
+type tokenCache struct {
+	mu     sync.Mutex
+	ranges []tokenRange // 按 from 升序，互不重叠
+}
+
+// lookup 查询区块所在范围的代币地址
+func (c *tokenCache) lookup(number uint64) (common.Address, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	i := sort.Search(len(c.ranges), func(i int) bool { return c.ranges[i].to >= number })
+	if i < len(c.ranges) && c.ranges[i].from <= number {
+		return c.ranges[i].token, true
+	}
+	return common.Address{}, false
+}
+
+// record 记录区块的代币地址，与紧邻的范围代币相同时合并
+func (c *tokenCache) record(number uint64, token common.Address) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	i := sort.Search(len(c.ranges), func(i int) bool { return c.ranges[i].from > number })
+	left, right := i-1, i
+	if left >= 0 && c.ranges[left].to >= number {
+		return // 已在范围内
+	}
+	mergeLeft := left >= 0 && c.ranges[left].token == token && c.ranges[left].to+1 == number
+	mergeRight := right < len(c.ranges) && c.ranges[right].token == token && number+1 == c.ranges[right].from
+
+	switch {
+	case mergeLeft && mergeRight:
+		c.ranges[left].to = c.ranges[right].to
+		c.ranges = append(c.ranges[:right], c.ranges[right+1:]...)
+	case mergeLeft:
+		c.ranges[left].to = number
+	case mergeRight:
+		c.ranges[right].from = number
+	default:
+		c.ranges = append(c.ranges, tokenRange{})
+		copy(c.ranges[right+1:], c.ranges[right:])
+		c.ranges[right] = tokenRange{from: number, to: number, token: token}
+		if len(c.ranges) > tokenCacheSize {
+			c.ranges = c.ranges[1:]
+		}
+	}
+}
+
+// resolveToken 查询事件所在区块时空投合约的代币地址，结果按区块范围缓存
+func (d *airdropDeployment) resolveToken(ctx context.Context, blockNumber uint64) (common.Address, error) {
+	if token, ok := d.tokens.lookup(blockNumber); ok {
+		return token, nil
+	}
+	token, err := d.contract.Token(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(blockNumber)})
+	if err != nil {
+		return common.Address{}, err
+	}
+	d.tokens.record(blockNumber, token)
+	return token, nil
+}
+
+// tokenRetryLoop 定期重新解析代币地址查询失败的事件
+func (d *airdropDeployment) tokenRetryLoop(ctx context.Context) {
+	ticker := time.NewTicker(tokenRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.retryUnresolvedTokens(ctx); err != nil {
+				d.log.Warn("重新解析空投代币地址失败", "err", err)
+			}
+		}
+	}
+}
+
+// retryUnresolvedTokens 按区块顺序重新解析代币地址未解析的事件，查询失败时留待下一轮
+func (d *airdropDeployment) retryUnresolvedTokens(ctx context.Context) error {
+	events, err := d.db.ListUnresolvedAirdropEvents(d.contractAddr, tokenRetryBatch)
+	if err != nil {
+		return err
+	}
+	for i, event := range events {
+		token, err := d.resolveToken(ctx, event.BlockNumber)
+		if err != nil {
+			return fmt.Errorf("获取代币地址失败（区块 %d，剩余 %d 个事件）: %w", event.BlockNumber, len(events)-i, err)
+		}
+		if err := d.db.ResolveAirdropEventToken(event, token); err != nil {
+			return err
+		}
+	}
+	if len(events) > 0 {
+		d.log.Info("已重新解析空投事件的代币地址", "count", len(events))
+	}
+	return nil
+}
+
+// AirdropStats 查询空投事件统计
+func (s *serviceImpl) AirdropStats(ctx context.Context) (*AirdropStats, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("数据库未初始化")
+	}
+	unresolved, err := s.db.CountUnresolvedAirdropEvents()
+	if err != nil {
+		return nil, err
+	}
+	return &AirdropStats{UnresolvedTokenEvents: unresolved}, nil
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenCache(t *testing.T) {
+	tokenA := common.HexToAddress("0xa")
+	tokenB := common.HexToAddress("0xb")
+
+	var c tokenCache
+	c.record(100, tokenA)
+	c.record(101, tokenA) // 与 100 相邻，合并为 [100, 101]
+	c.record(200, tokenA) // 不相邻，之间代币可能变更过
+	c.record(300, tokenB)
+	c.record(299, tokenB) // 与 300 相邻，合并为 [299, 300]
+
+	tests := []struct {
+		name   string
+		number uint64
+		token  common.Address
+		ok     bool
+	}{
+		{"范围之前", 99, common.Address{}, false},
+		{"合并后的范围", 101, tokenA, true},
+		{"不相邻的查询结果不合并", 150, common.Address{}, false},
+		{"单个区块", 200, tokenA, true},
+		{"向前合并的范围", 299, tokenB, true},
+		{"范围之后", 301, common.Address{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, ok := c.lookup(tt.number)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.token, token)
+		})
+	}
+
+	// 两侧都紧邻且代币相同时合并为一个范围
+	c.record(104, tokenA)
+	assert.Len(t, c.ranges, 4)
+	c.record(102, tokenA)
+	c.record(103, tokenA)
+	assert.Len(t, c.ranges, 3)
+	token, ok := c.lookup(104)
+	assert.True(t, ok)
+	assert.Equal(t, tokenA, token)
+}
@@ -2,32 +2,44 @@ package service
 
 import (
 	"context"
-	"math/big"
-	"time"
-	"sync/atomic"
+	"fmt"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/urfave/cli/v2"
 	"go-contracts/config"
 	"go-contracts/contract"
 	"go-contracts/database"
 	"go-contracts/models"
+	"go-contracts/synchronizer/node"
 	"go-contracts/util"
+	"gorm.io/gorm"
+	"math/big"
+	"sync/atomic"
+	"time"
 )
 
+// headerCacheSize 区块头缓存容量（同一批空投的事件通常位于同一区块）
+const headerCacheSize = 1024
+
 // AirdropWatcher 空投事件监听服务
-// 负责监听和处理AirdropERC20和AirdropBNB事件
+// 负责监听和处理AirdropERC20和AirdropBNB事件，支持同时监听多个空投合约部署
 // 实现了cycle.Service接口
 
 type AirdropWatcher struct {
-	shutdown    context.CancelCauseFunc  // 取消函数
-	stopped     atomic.Bool              // 停止状态标记
-	db          *database.DB             // 数据库连接
-	ethClient   *ethclient.Client        // 以太坊客户端
-	contract    *contract.Airdrop        // 空投合约实例
-	contractAddr common.Address          // 空投合约地址
+	shutdown      context.CancelCauseFunc // 取消函数
+	stopped       atomic.Bool             // 停止状态标记
+	db            *database.DB            // 数据库连接
+	ethClient     node.EthClient          // 以太坊客户端
+	confirmations uint64                  // 确认数（事件达到该深度后才保存）
+	rangeSize     uint64                  // 回填时每次查询日志的区块数
+	polling       bool                    // 节点不支持订阅，改为轮询 eth_getLogs
+	pollInterval  time.Duration           // 轮询间隔
+	maxRestarts   int                     // 订阅连续失败的最大重试次数，超过后关闭服务
+
+	headers     *lru.Cache[common.Hash, *types.Header] // 区块头缓存（按区块哈希，各合约共用）
+	deployments []*airdropDeployment                   // 监听的空投合约
 }
 
 // Stopped 实现cycle.Service接口，返回服务是否已停止
@@ -45,32 +57,59 @@ func NewAirdropWatcher(c *cli.Context, cfg *config.Config, shutdown context.Canc
 	}
 
 	// 初始化以太坊客户端
-	rawURL := config.RAW_URL
-	ethClient, err := ethclient.Dial(rawURL)
+	ethClient, err := node.DialEthClientPool(c.Context, &cfg.RPC)
 	if err != nil {
-		util.Log.Error("连接BSC节点失败", "url", rawURL, "err", err)
+		util.Log.Error("连接BSC节点失败", "endpoints", len(cfg.RPC.Endpoints), "err", err)
 		db.Close()
 		return nil, err
 	}
 
-	// 初始化空投合约实例
-	contractAddr := common.HexToAddress(config.AIRDROP_CONTRACT_ADDRESS)
-	airdropContract, err := contract.NewAirdrop(contractAddr, ethClient)
-	if err != nil {
-		util.Log.Error("初始化空投合约失败", "addr", contractAddr, "err", err)
-		db.Close()
-		ethClient.Close()
-		return nil, err
+	rangeSize := cfg.Airdrop.RangeSize
+	if rangeSize == 0 {
+		rangeSize = defaultAirdropRangeSize
+	}
+
+	pollInterval := time.Duration(cfg.Airdrop.PollInterval) * time.Second
+	if pollInterval <= 0 {
+		pollInterval = defaultAirdropPollInterval
 	}
 
-	return &AirdropWatcher{
-		shutdown:    shutdown,
-		db:          db,
-		ethClient:   ethClient,
-		contract:    airdropContract,
-		contractAddr: contractAddr,
-	},
-	nil
+	w := &AirdropWatcher{
+		shutdown:      shutdown,
+		db:            db,
+		ethClient:     ethClient,
+		confirmations: cfg.Indexer.Confirmations,
+		rangeSize:     rangeSize,
+		polling:       !ethClient.SupportsSubscriptions(),
+		pollInterval:  pollInterval,
+		maxRestarts:   cfg.Airdrop.MaxRestarts,
+		headers:       lru.NewCache[common.Hash, *types.Header](headerCacheSize),
+	}
+	if w.maxRestarts <= 0 {
+		w.maxRestarts = defaultMaxRestarts
+	}
+
+	// 初始化各空投合约（未配置时监听 AIRDROP_CONTRACT_ADDRESS）
+	contracts := cfg.Airdrop.Contracts
+	if len(contracts) == 0 {
+		contracts = []config.AirdropContractConfig{{Address: config.AIRDROP_CONTRACT_ADDRESS, StartBlock: cfg.Airdrop.StartBlock}}
+	}
+	seen := make(map[common.Address]bool)
+	for _, contractCfg := range contracts {
+		d, err := newAirdropDeployment(w, contractCfg)
+		if err == nil && seen[d.contractAddr] {
+			err = fmt.Errorf("空投合约重复配置: %s", d.contractAddr.Hex())
+		}
+		if err != nil {
+			util.Log.Error("初始化空投合约失败", "addr", contractCfg.Address, "err", err)
+			db.Close()
+			ethClient.Close()
+			return nil, err
+		}
+		seen[d.contractAddr] = true
+		w.deployments = append(w.deployments, d)
+	}
+	return w, nil
 }
 
 // Start 启动监听服务
@@ -79,12 +118,21 @@ func (w *AirdropWatcher) Start(ctx context.Context) error {
 		return nil
 	}
 
-	util.Log.Info("空投事件监听服务启动", "contract", w.contractAddr.Hex())
-	
-	// 启动两个事件监听协程
-	go w.watchAirdropERC20(ctx)
-	go w.watchAirdropBNB(ctx)
-	
+	util.Log.Info("空投事件监听服务启动", "contracts", len(w.deployments), "confirmations", w.confirmations, "polling", w.polling)
+	if w.polling {
+		util.Log.Warn("节点不支持订阅，空投事件改为轮询", "interval", w.pollInterval)
+	}
+	if legacy, err := w.db.CountLegacyAirdropEvents(); err != nil {
+		util.Log.Warn("统计旧版空投事件失败", "err", err)
+	} else if legacy > 0 {
+		util.Log.Warn("存在没有 log_index 的旧版空投事件，执行 airdrop-reindex 后重新回填", "count", legacy)
+	}
+
+	for _, d := range w.deployments {
+		if err := d.start(ctx); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -92,107 +140,114 @@ func (w *AirdropWatcher) Start(ctx context.Context) error {
 func (w *AirdropWatcher) Stop(ctx context.Context) error {
 	if w.stopped.CompareAndSwap(false, true) {
 		util.Log.Info("空投事件监听服务停止中...")
-		
+
 		// 关闭资源
 		if w.db != nil {
 			w.db.Close()
 		}
-		
+
 		if w.ethClient != nil {
 			w.ethClient.Close()
 		}
-		
+
 		util.Log.Info("空投事件监听服务已停止")
 	}
 	return nil
 }
 
-// watchAirdropERC20 监听AirdropERC20事件
-func (w *AirdropWatcher) watchAirdropERC20(ctx context.Context) {
+// watchAirdropERC20 监听AirdropERC20事件，订阅出错时返回错误由 superviseSubscription 负责重新订阅
+// 订阅建立后调用 subscribed
+func (d *airdropDeployment) watchAirdropERC20(ctx context.Context, subscribed func()) error {
 	// 创建事件过滤器
 	query := &bind.WatchOpts{
 		Context: ctx,
 	}
-	
+
 	// 创建事件接收通道
 	logs := make(chan *contract.AirdropAirdropERC20)
-	
+
 	// 监听事件
-	sub, err := w.contract.WatchAirdropERC20(query, logs, []common.Address{})
+	sub, err := d.contract.WatchAirdropERC20(query, logs, []common.Address{})
 	if err != nil {
-		util.Log.Error("监听AirdropERC20事件失败", "err", err)
-		w.shutdown(err)
-		return
+		return fmt.Errorf("监听AirdropERC20事件失败: %w", err)
 	}
 	defer sub.Unsubscribe()
-	
-	util.Log.Info("开始监听AirdropERC20事件")
-	
+
+	d.log.Info("开始监听AirdropERC20事件")
+	subscribed()
+
 	// 处理事件流
 	for {
 		select {
 		case <-ctx.Done():
-			util.Log.Info("AirdropERC20事件监听停止")
-			return
+			d.log.Info("AirdropERC20事件监听停止")
+			return nil
 		case err := <-sub.Err():
-			util.Log.Error("AirdropERC20事件订阅错误", "err", err)
-			// 不立即终止服务，尝试重新连接
-			util.Log.Info("尝试重新监听AirdropERC20事件")
-			go w.watchAirdropERC20(ctx)
-			return
+			return fmt.Errorf("AirdropERC20事件订阅错误: %w", err)
 		case event := <-logs:
 			// 处理单个事件
-			w.handleAirdropEvent(ctx, "AirdropERC20", event)
+			d.enqueueEvent(ctx, models.AirdropTypeERC20, event, event.Raw)
 		}
 	}
 }
 
-// watchAirdropBNB 监听AirdropBNB事件
-func (w *AirdropWatcher) watchAirdropBNB(ctx context.Context) {
+// watchAirdropBNB 监听AirdropBNB事件，订阅出错时返回错误由 superviseSubscription 负责重新订阅
+// 订阅建立后调用 subscribed
+func (d *airdropDeployment) watchAirdropBNB(ctx context.Context, subscribed func()) error {
 	// 创建事件过滤器
 	query := &bind.WatchOpts{
 		Context: ctx,
 	}
-	
+
 	// 创建事件接收通道
 	logs := make(chan *contract.AirdropAirdropBNB)
-	
+
 	// 监听事件
-	sub, err := w.contract.WatchAirdropBNB(query, logs, []common.Address{})
+	sub, err := d.contract.WatchAirdropBNB(query, logs, []common.Address{})
 	if err != nil {
-		util.Log.Error("监听AirdropBNB事件失败", "err", err)
-		w.shutdown(err)
-		return
+		return fmt.Errorf("监听AirdropBNB事件失败: %w", err)
 	}
 	defer sub.Unsubscribe()
-	
-	util.Log.Info("开始监听AirdropBNB事件")
-	
+
+	d.log.Info("开始监听AirdropBNB事件")
+	subscribed()
+
 	// 处理事件流
 	for {
 		select {
 		case <-ctx.Done():
-			util.Log.Info("AirdropBNB事件监听停止")
-			return
+			d.log.Info("AirdropBNB事件监听停止")
+			return nil
 		case err := <-sub.Err():
-			util.Log.Error("AirdropBNB事件订阅错误", "err", err)
-			// 不立即终止服务，尝试重新连接
-			util.Log.Info("尝试重新监听AirdropBNB事件")
-			go w.watchAirdropBNB(ctx)
-			return
+			return fmt.Errorf("AirdropBNB事件订阅错误: %w", err)
 		case event := <-logs:
 			// 处理单个事件
-			w.handleAirdropEvent(ctx, "AirdropBNB", event)
+			d.enqueueEvent(ctx, models.AirdropTypeBNB, event, event.Raw)
 		}
 	}
 }
 
+// headerByHash 获取区块头，优先读取缓存
+// 以区块哈希为键，重组后的新区块哈希不同，不会读到孤块的区块头
+func (w *AirdropWatcher) headerByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	if header, ok := w.headers.Get(hash); ok {
+		return header, nil
+	}
+	header, err := w.ethClient.HeaderByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	w.headers.Add(hash, header)
+	return header, nil
+}
+
 // handleAirdropEvent 处理空投事件
-func (w *AirdropWatcher) handleAirdropEvent(ctx context.Context, eventType string, event interface{}) {
+// 返回错误时调用方应保留事件稍后重试，检查点不会越过该事件
+func (d *airdropDeployment) handleAirdropEvent(ctx context.Context, eventType string, event interface{}) error {
 	var recipient common.Address
 	var amount *big.Int
 	var rawLog types.Log
-	
+
 	switch e := event.(type) {
 	case *contract.AirdropAirdropERC20:
 		recipient = e.Recipient
@@ -203,46 +258,54 @@ func (w *AirdropWatcher) handleAirdropEvent(ctx context.Context, eventType strin
 		amount = e.Amount
 		rawLog = e.Raw
 	default:
-		util.Log.Error("未知的事件类型", "type", eventType)
-		return
+		return fmt.Errorf("未知的事件类型: %s", eventType)
 	}
-	
-	// 获取区块信息
-	block, err := w.ethClient.BlockByHash(ctx, rawLog.BlockHash)
+
+	// 获取区块头（同一区块的多个事件共用缓存）
+	header, err := d.headerByHash(ctx, rawLog.BlockHash)
 	if err != nil {
-		util.Log.Error("获取区块信息失败", "hash", rawLog.BlockHash.Hex(), "err", err)
-		return
+		return fmt.Errorf("获取区块头失败（%s）: %w", rawLog.BlockHash.Hex(), err)
 	}
-	
+
 	// 创建事件记录
 	dbEvent := &models.AirdropEvent{
 		TransactionHash: rawLog.TxHash,
-		BlockNumber:     block.NumberU64(),
-		BlockTime:       time.Unix(int64(block.Time()), 0),
+		LogIndex:        rawLog.Index,
+		BlockHash:       rawLog.BlockHash,
+		BlockNumber:     header.Number.Uint64(),
+		BlockTime:       time.Unix(int64(header.Time), 0),
 		EventType:       eventType,
 		Recipient:       recipient,
 		Amount:          amount.String(),
-		ContractAddress: w.contractAddr,
+		ContractAddress: d.contractAddr,
 	}
-	
+
 	// 根据事件类型设置TokenAddress
-	if eventType == "AirdropBNB" {
+	if eventType == models.AirdropTypeBNB {
 		dbEvent.TokenAddress = common.HexToAddress(config.NATIVE_TOKEN_ADDRESS)
+		dbEvent.TokenResolved = true
 	} else {
-		// 获取代币地址
-		tokenAddr, err := w.contract.Token(&bind.CallOpts{Context: ctx})
+		// 获取事件所在区块时的代币地址；失败时标记为未解析，由 tokenRetryLoop 定期重新解析
+		tokenAddr, err := d.resolveToken(ctx, dbEvent.BlockNumber)
 		if err == nil {
 			dbEvent.TokenAddress = tokenAddr
+			dbEvent.TokenResolved = true
 		} else {
-			util.Log.Warn("获取代币地址失败", "err", err)
+			d.log.Warn("获取代币地址失败，事件标记为代币未解析", "tx", rawLog.TxHash.Hex(), "block", dbEvent.BlockNumber, "err", err)
 		}
 	}
-	
-	// 保存到数据库
-	if err := w.db.Create(dbEvent).Error;
-	err != nil {
-		util.Log.Error("保存空投事件失败", "err", err, "event", dbEvent)
-	} else {
-		util.Log.Info("空投事件保存成功", "type", eventType, "recipient", recipient.Hex(), "amount", amount.String())
+
+	// 保存到数据库（重复的事件更新原记录，重启、重新订阅、回填都不会产生重复记录）
+	// 同一事务中将事件匹配到本服务发起的空投任务
+	err = d.db.Transaction(func(tx *gorm.DB) error {
+		if err := database.UpsertAirdropEvent(tx, dbEvent); err != nil {
+			return err
+		}
+		return database.MatchAirdropEvent(tx, dbEvent)
+	})
+	if err != nil {
+		return fmt.Errorf("保存空投事件失败（%s）: %w", rawLog.TxHash.Hex(), err)
 	}
-}
\ No newline at end of file
+	d.log.Info("空投事件保存成功", "type", eventType, "recipient", recipient.Hex(), "amount", amount.String())
+	return nil
+}
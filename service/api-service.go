@@ -5,21 +5,22 @@ import (
 	"fmt"
 	"go-contracts/config"
 	"go-contracts/contract"
+	"go-contracts/database"
 	"go-contracts/models"
+	"go-contracts/signer"
 	"go-contracts/synchronizer/node"
 	"go-contracts/util"
 	"math/big"
-	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 type AirdropParams struct {
 	Recipients []string `json:"recipients"` // 接收者地址数组
 	Amounts    []string `json:"amounts"`    // 金额数组（字符串形式）
+	DryRun     bool     `json:"dry_run"`    // 只模拟执行，返回预计费用和失败原因，不发送交易
 }
 
 // GetBlockParams 获取区块信息的请求参数
@@ -45,21 +46,24 @@ type ERC20ApproveParams struct {
 	ERC20ContractParams
 	Spender string `json:"spender"` // 被授权方地址
 	Value   string `json:"value"`   // 授权金额
+	DryRun  bool   `json:"dry_run"` // 只模拟执行，不发送交易
 }
 
 // ERC20TransferParams 转账的参数
 type ERC20TransferParams struct {
 	ERC20ContractParams
-	To    string `json:"to"`    // 接收方地址
-	Value string `json:"value"` // 转账金额
+	To     string `json:"to"`      // 接收方地址
+	Value  string `json:"value"`   // 转账金额
+	DryRun bool   `json:"dry_run"` // 只模拟执行，不发送交易
 }
 
 // ERC20TransferFromParams 授权转账的参数
 type ERC20TransferFromParams struct {
 	ERC20ContractParams
-	From  string `json:"from"`  // 发送方地址
-	To    string `json:"to"`    // 接收方地址
-	Value string `json:"value"` // 转账金额
+	From   string `json:"from"`    // 发送方地址
+	To     string `json:"to"`      // 接收方地址
+	Value  string `json:"value"`   // 转账金额
+	DryRun bool   `json:"dry_run"` // 只模拟执行，不发送交易
 }
 
 // ERC20BalanceParams 查询余额的参数
@@ -74,8 +78,10 @@ type AirdropSetGovParams struct {
 }
 
 type Service interface {
-	AirdropBnb(ctx context.Context, params AirdropParams) error
-	AirdropERC20(ctx context.Context, params AirdropParams) error
+	AirdropBnb(ctx context.Context, params AirdropParams) (*models.AirdropJob, error)
+	AirdropERC20(ctx context.Context, params AirdropParams) (*models.AirdropJob, error)
+	GetAirdropJob(ctx context.Context, id uint) (*AirdropJobResult, error) // 查询空投任务及各接收者状态
+	AirdropStats(ctx context.Context) (*AirdropStats, error)               // 查询空投事件统计
 	AirdropSetGov(ctx context.Context, params AirdropSetGovParams) error
 	AirdropGov(ctx context.Context) (string, error)
 	// 区块相关方法
@@ -85,13 +91,28 @@ type Service interface {
 	GetLatestBlock(ctx context.Context) (*models.Block, error)
 
 	// ERC20相关方法
-	ERC20Allowance(ctx context.Context, params ERC20AllowanceParams) (*big.Int, error)       // 查询授权额度
-	ERC20Approve(ctx context.Context, params ERC20ApproveParams) (*big.Int, error)           // 授权
-	ERC20Transfer(ctx context.Context, params ERC20TransferParams) (*big.Int, error)         // 转账
-	ERC20TransferFrom(ctx context.Context, params ERC20TransferFromParams) (*big.Int, error) // 从授权地址转账
-	ERC20Balance(ctx context.Context, params ERC20BalanceParams) (*big.Int, error)           // 查询余额
+	ERC20Allowance(ctx context.Context, params ERC20AllowanceParams) (*big.Int, error)     // 查询授权额度
+	ERC20Approve(ctx context.Context, params ERC20ApproveParams) (string, error)           // 授权，返回交易哈希
+	ERC20Transfer(ctx context.Context, params ERC20TransferParams) (string, error)         // 转账，返回交易哈希
+	ERC20TransferFrom(ctx context.Context, params ERC20TransferFromParams) (string, error) // 从授权地址转账，返回交易哈希
+	ERC20Balance(ctx context.Context, params ERC20BalanceParams) (*big.Int, error)         // 查询余额
 	ERC20TotalSupply(ctx context.Context, params ERC20ContractParams) (*big.Int, error)
 	ERC20TokenInfo(ctx context.Context, params ERC20ContractParams) (*models.ERC20TokenInfo, error)
+
+	// 写操作的模拟执行（dry_run），不发送交易
+	SimulateAirdropBnb(ctx context.Context, params AirdropParams) (*SimulationResult, error)
+	SimulateAirdropERC20(ctx context.Context, params AirdropParams) (*SimulationResult, error)
+	SimulateERC20Approve(ctx context.Context, params ERC20ApproveParams) (*SimulationResult, error)
+	SimulateERC20Transfer(ctx context.Context, params ERC20TransferParams) (*SimulationResult, error)
+	SimulateERC20TransferFrom(ctx context.Context, params ERC20TransferFromParams) (*SimulationResult, error)
+
+	// 已发送交易相关方法
+	GetTransaction(ctx context.Context, txHash string) (*models.SentTransaction, error)     // 查询交易状态
+	SpeedUpTransaction(ctx context.Context, txHash string) (*models.SentTransaction, error) // 加速交易，返回替换交易
+	CancelTransaction(ctx context.Context, txHash string) (*models.SentTransaction, error)  // 取消交易，返回取消交易
+
+	// 节点连接池统计
+	NodeStats(ctx context.Context) []node.EndpointStats
 }
 
 type serviceImpl struct {
@@ -99,185 +120,101 @@ type serviceImpl struct {
 
 	// 区块链客户端接口
 	ethClient node.EthClient
+
+	// 数据库连接（空投任务记录）
+	db *database.DB
+
+	// 交易发送配置（Gas 余量、费用上限）
+	txCfg config.TxConfig
+
+	// 交易签名器
+	signer signer.Signer
+
+	// 签名账户的 nonce 分配器（未配置签名器或数据库时为 nil，由合约绑定自行查询 nonce）
+	nonces *nonceManager
+
+	// 交易跟踪（查询交易状态时立即检查回执，未配置数据库时为 nil）
+	tracker *TxTracker
+
+	// 索引服务索引的 ERC20 合约，只有这些合约的交易会先记录为待确认、再由索引结果替换
+	indexedERC20 map[common.Address]bool
 }
 
 var _ Service = (*serviceImpl)(nil)
 
-func New(validator util.Validator, ethClient node.EthClient) Service {
-	return &serviceImpl{
+func New(validator util.Validator, ethClient node.EthClient, db *database.DB, txCfg config.TxConfig, erc20Contracts []string, txSigner signer.Signer) Service {
+	s := &serviceImpl{
 		validator: validator,
 
 		ethClient: ethClient,
+		db:        db,
+		txCfg:     txCfg,
+		signer:    txSigner,
+
+		indexedERC20: make(map[common.Address]bool, len(erc20Contracts)),
 	}
-}
-func (s *serviceImpl) AirdropBnb(ctx context.Context, params AirdropParams) error {
-	// 1. 验证参数
-	if len(params.Recipients) == 0 || len(params.Amounts) == 0 {
-		return fmt.Errorf("接收者地址和金额不能为空")
-	}
-	if len(params.Recipients) != len(params.Amounts) {
-		return fmt.Errorf("接收者地址数量和金额数量不匹配")
+	for _, addr := range erc20Contracts {
+		s.indexedERC20[common.HexToAddress(addr)] = true
 	}
-
-	// 2. 连接到区块链节点
-	client, err := ethclient.Dial(config.RAW_URL)
-	if err != nil {
-		return fmt.Errorf("连接区块链节点失败: %w", err)
+	if txSigner != nil && db != nil {
+		s.nonces = newNonceManager(ethClient, db, txSigner.Address())
 	}
-	defer client.Close()
-
-	// 3. 解析私钥
-	privateKey, err := crypto.HexToECDSA(config.PRIVATE_KEY)
-	if err != nil {
-		return fmt.Errorf("解析私钥失败: %w", err)
+	if db != nil {
+		s.tracker = NewTxTracker(db, ethClient, txCfg)
 	}
-
-	// 4. 创建交易选项
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(97)) // 97是BSC测试网的链ID
+	return s
+}
+func (s *serviceImpl) AirdropBnb(ctx context.Context, params AirdropParams) (*models.AirdropJob, error) {
+	// 1. 解析接收者地址和金额
+	recipients, amounts, err := parseAirdropParams(params)
 	if err != nil {
-		return fmt.Errorf("创建交易选项失败: %w", err)
+		return nil, err
 	}
 
-	// 5. 获取当前Gas价格
-	gasPrice, err := client.SuggestGasPrice(ctx)
+	// 2. 创建交易选项（签名账户与交易费用）
+	auth, err := s.newTransactOpts(ctx)
 	if err != nil {
-		// 如果获取失败，使用默认值
-		gasPrice = big.NewInt(10000000000) // 10 Gwei
-		util.Log.Warn("获取Gas价格失败，使用默认值", "error", err)
+		return nil, err
 	}
-	auth.GasPrice = gasPrice
-	auth.GasLimit = 3000000 // 设置Gas上限
 
-	// 6. 解析合约地址
+	// 3. 解析合约地址
 	contractAddress := common.HexToAddress(config.AIRDROP_CONTRACT_ADDRESS)
 
-	// 7. 创建合约实例
-	airdropContract, err := contract.NewAirdropTransactor(contractAddress, client)
-	if err != nil {
-		return fmt.Errorf("创建空投合约实例失败: %w", err)
-	}
-
-	// 8. 转换接收者地址和金额
-	recipients := make([]common.Address, len(params.Recipients))
-	amounts := make([]*big.Int, len(params.Amounts))
-
-	for i, addrStr := range params.Recipients {
-		if !common.IsHexAddress(addrStr) {
-			return fmt.Errorf("无效的以太坊地址: %s", addrStr)
-		}
-		recipients[i] = common.HexToAddress(addrStr)
-	}
-
-	for i, amountStr := range params.Amounts {
-		amount, ok := new(big.Int).SetString(amountStr, 10)
-		if !ok {
-			return fmt.Errorf("无效的金额格式: %s", amountStr)
-		}
-		amounts[i] = amount
-	}
-
-	// 9. 计算总金额并设置交易价值
-	totalAmount := new(big.Int)
-	for _, amount := range amounts {
-		totalAmount.Add(totalAmount, amount)
-	}
-	auth.Value = totalAmount
-
-	// 10. 调用空投合约方法
-	tx, err := airdropContract.AirdropBNB(auth, recipients, amounts)
+	// 4. 创建合约实例
+	airdropContract, err := contract.NewAirdropTransactor(contractAddress, s.ethClient)
 	if err != nil {
-		return fmt.Errorf("调用空投合约失败: %w", err)
+		return nil, fmt.Errorf("创建空投合约实例失败: %w", err)
 	}
 
-	// 11. 记录交易信息
-	util.Log.Info("BNB空投交易已发送", "txHash", tx.Hash().Hex())
-
-	// 12. 可以在这里添加数据库记录逻辑
-	// 例如保存交易哈希、接收者、金额等信息到数据库
-
-	return nil
+	// 5. 按Gas预算切分并依次发送（每笔交易的价值为该分片的金额总和）
+	return s.sendAirdrop(ctx, models.AirdropTypeBNB, auth, contractAddress, airdropContract.AirdropBNB, recipients, amounts)
 }
 
 // AirdropERC20 实现ERC20代币空投功能
-func (s *serviceImpl) AirdropERC20(ctx context.Context, params AirdropParams) error {
-	// 1. 验证参数
-	if len(params.Recipients) == 0 || len(params.Amounts) == 0 {
-		return fmt.Errorf("接收者地址和金额不能为空")
-	}
-	if len(params.Recipients) != len(params.Amounts) {
-		return fmt.Errorf("接收者地址数量和金额数量不匹配")
-	}
-
-	// 2. 连接到区块链节点
-	client, err := ethclient.Dial(config.RAW_URL)
+func (s *serviceImpl) AirdropERC20(ctx context.Context, params AirdropParams) (*models.AirdropJob, error) {
+	// 1. 解析接收者地址和金额
+	recipients, amounts, err := parseAirdropParams(params)
 	if err != nil {
-		return fmt.Errorf("连接区块链节点失败: %w", err)
-	}
-	defer client.Close()
-
-	// 3. 解析私钥
-	privateKey, err := crypto.HexToECDSA(config.PRIVATE_KEY)
-	if err != nil {
-		return fmt.Errorf("解析私钥失败: %w", err)
-	}
-
-	// 4. 创建交易选项
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(97)) // 97是BSC测试网的链ID
-	if err != nil {
-		return fmt.Errorf("创建交易选项失败: %w", err)
+		return nil, err
 	}
 
-	// 5. 获取当前Gas价格
-	gasPrice, err := client.SuggestGasPrice(ctx)
+	// 2. 创建交易选项（签名账户与交易费用）
+	auth, err := s.newTransactOpts(ctx)
 	if err != nil {
-		// 如果获取失败，使用默认值
-		gasPrice = big.NewInt(10000000000) // 10 Gwei
-		util.Log.Warn("获取Gas价格失败，使用默认值", "error", err)
+		return nil, err
 	}
-	auth.GasPrice = gasPrice
-	auth.GasLimit = 3000000 // 设置Gas上限
 
-	// 6. 解析合约地址
+	// 3. 解析合约地址
 	contractAddress := common.HexToAddress(config.AIRDROP_CONTRACT_ADDRESS)
 
-	// 7. 创建合约实例
-	airdropContract, err := contract.NewAirdropTransactor(contractAddress, client)
-	if err != nil {
-		return fmt.Errorf("创建空投合约实例失败: %w", err)
-	}
-
-	// 8. 转换接收者地址和金额
-	recipients := make([]common.Address, len(params.Recipients))
-	amounts := make([]*big.Int, len(params.Amounts))
-
-	for i, addrStr := range params.Recipients {
-		if !common.IsHexAddress(addrStr) {
-			return fmt.Errorf("无效的以太坊地址: %s", addrStr)
-		}
-		recipients[i] = common.HexToAddress(addrStr)
-	}
-
-	for i, amountStr := range params.Amounts {
-		amount, ok := new(big.Int).SetString(amountStr, 10)
-		if !ok {
-			return fmt.Errorf("无效的金额格式: %s", amountStr)
-		}
-		amounts[i] = amount
-	}
-
-	// 9. 调用ERC20空投合约方法
-	tx, err := airdropContract.AirdropERC20(auth, recipients, amounts)
+	// 4. 创建合约实例
+	airdropContract, err := contract.NewAirdropTransactor(contractAddress, s.ethClient)
 	if err != nil {
-		return fmt.Errorf("调用ERC20空投合约失败: %w", err)
+		return nil, fmt.Errorf("创建空投合约实例失败: %w", err)
 	}
 
-	// 10. 记录交易信息
-	util.Log.Info("ERC20空投交易已发送", "txHash", tx.Hash().Hex())
-
-	// 11. 可以在这里添加数据库记录逻辑
-	// 例如保存交易哈希、接收者、金额等信息到数据库
-
-	return nil
+	// 5. 按Gas预算切分并依次发送
+	return s.sendAirdrop(ctx, models.AirdropTypeERC20, auth, contractAddress, airdropContract.AirdropERC20, recipients, amounts)
 }
 
 // GetBlockByNumber 根据区块号获取区块信息
@@ -292,25 +229,32 @@ func (s *serviceImpl) GetBlockByNumber(ctx context.Context, blockNumber uint64)
 		return nil, fmt.Errorf("区块链客户端未初始化")
 	}
 
-	// 注意：当前的 EthClient 接口不直接支持获取区块信息
-	// 这里仅返回一个示例区块数据用于演示
-	now := time.Now()
-	result := &models.Block{
-		BlockNumber: blockNumber,
-		BlockHash:   fmt.Sprintf("0xblockhash%v", blockNumber),
-		ParentHash:  fmt.Sprintf("0xparenthash%v", blockNumber-1),
-		Time:        uint64(now.Unix()),
-		Timestamp:   now,
+	block, err := s.ethClient.BlockByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return nil, fmt.Errorf("查询区块失败（区块号: %d）: %w", blockNumber, err)
 	}
-
-	return result, nil
+	return blockToModel(block), nil
 }
 
 // GetBlockByHash 根据区块哈希获取区块信息
 func (s *serviceImpl) GetBlockByHash(ctx context.Context, blockHash string) (*models.Block, error) {
-	var block models.Block
-	// 实际实现：从数据库查询区块信息
-	return &block, nil
+	if s.ethClient == nil {
+		return nil, fmt.Errorf("区块链客户端未初始化")
+	}
+
+	block, err := s.ethClient.BlockByHash(ctx, common.HexToHash(blockHash))
+	if err != nil {
+		return nil, fmt.Errorf("查询区块失败（区块哈希: %s）: %w", blockHash, err)
+	}
+	return blockToModel(block), nil
+}
+
+// blockToModel 将链上区块转换为区块实体
+func blockToModel(block *types.Block) *models.Block {
+	return models.NewBlockFromRPC(block.NumberU64(), block.Hash(), block.ParentHash(),
+		block.TxHash(), block.ReceiptHash(), block.Root(),
+		block.Coinbase(), block.GasUsed(), block.GasLimit(), block.Time(),
+		block.Extra(), len(block.Transactions()))
 }
 
 // SaveBlock 保存区块信息到数据库
@@ -321,9 +265,15 @@ func (s *serviceImpl) SaveBlock(ctx context.Context, block *models.Block) error
 
 // GetLatestBlock 获取最新区块信息
 func (s *serviceImpl) GetLatestBlock(ctx context.Context) (*models.Block, error) {
-	var block models.Block
-	// 实际实现：获取数据库中最新的区块信息
-	return &block, nil
+	if s.ethClient == nil {
+		return nil, fmt.Errorf("区块链客户端未初始化")
+	}
+
+	block, err := s.ethClient.BlockByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("查询最新区块失败: %w", err)
+	}
+	return blockToModel(block), nil
 }
 
 // ERC20Allowance 查询授权额度
@@ -335,28 +285,98 @@ func (s *serviceImpl) ERC20Allowance(ctx context.Context, params ERC20AllowanceP
 	return s.ethClient.ERC20Allowance(ctx, contractAddress, owner, spender)
 }
 
-// ERC20Approve 设置授权
-func (s *serviceImpl) ERC20Approve(ctx context.Context, params ERC20ApproveParams) (*big.Int, error) {
-	// 这里需要实现从某处获取TransactOpts的逻辑
-	// 实际应用中通常会从配置或数据库中获取私钥
-	// 为了示例，这里简化处理
-	return nil, fmt.Errorf("未实现的方法: ERC20Approve")
+// ERC20Approve 设置授权，返回交易哈希
+func (s *serviceImpl) ERC20Approve(ctx context.Context, params ERC20ApproveParams) (string, error) {
+	// 1. 解析参数
+	contractAddress, spender, value, err := params.parse()
+	if err != nil {
+		return "", err
+	}
+
+	// 2. 创建交易选项（签名账户与交易费用）
+	auth, err := s.newTransactOpts(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// 3. 发送交易
+	tx, err := s.transact(auth, models.TxKindERC20Approve, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return s.ethClient.ERC20Approve(ctx, contractAddress, opts, spender, value)
+	})
+	if err != nil {
+		return "", fmt.Errorf("授权失败: %w", err)
+	}
+
+	// 4. 记录交易
+	s.recordERC20Transaction(tx, models.ERC20TypeApprove, contractAddress, auth.From, spender, value)
+	return tx.Hash().Hex(), nil
 }
 
-// ERC20Transfer 转账
-func (s *serviceImpl) ERC20Transfer(ctx context.Context, params ERC20TransferParams) (*big.Int, error) {
-	// 这里需要实现从某处获取TransactOpts的逻辑
-	// 实际应用中通常会从配置或数据库中获取私钥
-	// 为了示例，这里简化处理
-	return nil, fmt.Errorf("未实现的方法: ERC20Transfer")
+// ERC20Transfer 转账，返回交易哈希
+func (s *serviceImpl) ERC20Transfer(ctx context.Context, params ERC20TransferParams) (string, error) {
+	// 1. 解析参数
+	contractAddress, to, value, err := params.parse()
+	if err != nil {
+		return "", err
+	}
+
+	// 2. 创建交易选项（签名账户与交易费用）
+	auth, err := s.newTransactOpts(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// 3. 检查签名账户余额
+	if err := s.checkERC20Balance(ctx, contractAddress, auth.From, value); err != nil {
+		return "", err
+	}
+
+	// 4. 发送交易
+	tx, err := s.transact(auth, models.TxKindERC20Transfer, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return s.ethClient.ERC20Transfer(ctx, contractAddress, opts, to, value)
+	})
+	if err != nil {
+		return "", fmt.Errorf("转账失败: %w", err)
+	}
+
+	// 5. 记录交易
+	s.recordERC20Transaction(tx, models.ERC20TypeTransfer, contractAddress, auth.From, to, value)
+	return tx.Hash().Hex(), nil
 }
 
-// ERC20TransferFrom 授权转账
-func (s *serviceImpl) ERC20TransferFrom(ctx context.Context, params ERC20TransferFromParams) (*big.Int, error) {
-	// 这里需要实现从某处获取TransactOpts的逻辑
-	// 实际应用中通常会从配置或数据库中获取私钥
-	// 为了示例，这里简化处理
-	return nil, fmt.Errorf("未实现的方法: ERC20TransferFrom")
+// ERC20TransferFrom 授权转账，返回交易哈希
+func (s *serviceImpl) ERC20TransferFrom(ctx context.Context, params ERC20TransferFromParams) (string, error) {
+	// 1. 解析参数
+	contractAddress, from, to, value, err := params.parse()
+	if err != nil {
+		return "", err
+	}
+
+	// 2. 创建交易选项（签名账户与交易费用）
+	auth, err := s.newTransactOpts(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// 3. 检查发送方余额及其对签名账户的授权额度
+	if err := s.checkERC20Balance(ctx, contractAddress, from, value); err != nil {
+		return "", err
+	}
+	if err := s.checkERC20Allowance(ctx, contractAddress, from, auth.From, value); err != nil {
+		return "", err
+	}
+
+	// 4. 发送交易
+	tx, err := s.transact(auth, models.TxKindERC20TransferFrom, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return s.ethClient.ERC20TransferFrom(ctx, contractAddress, opts, from, to, value)
+	})
+	if err != nil {
+		return "", fmt.Errorf("授权转账失败: %w", err)
+	}
+
+	// 5. 记录交易
+	s.recordERC20Transaction(tx, models.ERC20TypeTransferFrom, contractAddress, from, to, value)
+	return tx.Hash().Hex(), nil
 }
 
 // ERC20Balance 查询余额
@@ -398,59 +418,43 @@ func (s *serviceImpl) ERC20TokenInfo(ctx context.Context, params ERC20ContractPa
 		nil
 }
 
+// NodeStats 返回各区块链节点的健康状态和请求统计
+func (s *serviceImpl) NodeStats(ctx context.Context) []node.EndpointStats {
+	return s.ethClient.EndpointStats()
+}
+
 // AirdropSetGov 设置空投合约授权地址
 func (s *serviceImpl) AirdropSetGov(ctx context.Context, params AirdropSetGovParams) error {
-	// 1. 连接到区块链节点
-	client, err := ethclient.Dial(config.RAW_URL)
-	if err != nil {
-		return fmt.Errorf("连接区块链节点失败: %w", err)
-	}
-	defer client.Close()
-
-	// 2. 解析私钥
-	privateKey, err := crypto.HexToECDSA(config.PRIVATE_KEY)
+	// 1. 创建交易选项（签名账户与交易费用）
+	auth, err := s.newTransactOpts(ctx)
 	if err != nil {
-		return fmt.Errorf("解析私钥失败: %w", err)
+		return err
 	}
 
-	// 3. 创建交易选项
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(97)) // 97是BSC测试网的链ID
-	if err != nil {
-		return fmt.Errorf("创建交易选项失败: %w", err)
-	}
-
-	// 4. 获取当前Gas价格
-	gasPrice, err := client.SuggestGasPrice(ctx)
-	if err != nil {
-		// 如果获取失败，使用默认值
-		gasPrice = big.NewInt(10000000000) // 10 Gwei
-		util.Log.Warn("获取Gas价格失败，使用默认值", "error", err)
-	}
-	auth.GasPrice = gasPrice
-	auth.GasLimit = 3000000 // 设置Gas上限
-
-	// 5. 解析合约地址
+	// 2. 解析合约地址
 	contractAddress := common.HexToAddress(config.AIRDROP_CONTRACT_ADDRESS)
 
-	// 6. 创建合约实例
-	airdropContract, err := contract.NewAirdropTransactor(contractAddress, client)
+	// 3. 创建合约实例
+	airdropContract, err := contract.NewAirdropTransactor(contractAddress, s.ethClient)
 	if err != nil {
 		return fmt.Errorf("创建空投合约实例失败: %w", err)
 	}
 
-	// 7. 验证并解析新的授权地址
+	// 4. 验证并解析新的授权地址
 	if !common.IsHexAddress(params.NewGov) {
 		return fmt.Errorf("无效的以太坊地址: %s", params.NewGov)
 	}
 	newGovAddr := common.HexToAddress(params.NewGov)
 
-	// 8. 调用setGov方法
-	tx, err := airdropContract.SetGov(auth, newGovAddr)
+	// 5. 调用setGov方法
+	tx, err := s.transact(auth, models.TxKindSetGov, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return airdropContract.SetGov(opts, newGovAddr)
+	})
 	if err != nil {
 		return fmt.Errorf("调用setGov方法失败: %w", err)
 	}
 
-	// 9. 记录交易信息
+	// 6. 记录交易信息
 	util.Log.Info("设置空投合约授权地址交易已发送", "txHash", tx.Hash().Hex(), "newGov", params.NewGov)
 
 	return nil
@@ -458,28 +462,21 @@ func (s *serviceImpl) AirdropSetGov(ctx context.Context, params AirdropSetGovPar
 
 // AirdropGov 查询空投合约授权地址
 func (s *serviceImpl) AirdropGov(ctx context.Context) (string, error) {
-	// 1. 连接到区块链节点
-	client, err := ethclient.Dial(config.RAW_URL)
-	if err != nil {
-		return "", fmt.Errorf("连接区块链节点失败: %w", err)
-	}
-	defer client.Close()
-
-	// 2. 解析合约地址
+	// 1. 解析合约地址
 	contractAddress := common.HexToAddress(config.AIRDROP_CONTRACT_ADDRESS)
 
-	// 3. 创建合约实例（只读）
-	airdropContract, err := contract.NewAirdropCaller(contractAddress, client)
+	// 2. 创建合约实例（只读）
+	airdropContract, err := contract.NewAirdropCaller(contractAddress, s.ethClient)
 	if err != nil {
 		return "", fmt.Errorf("创建空投合约只读实例失败: %w", err)
 	}
 
-	// 4. 调用gov方法查询授权地址
+	// 3. 调用gov方法查询授权地址
 	govAddr, err := airdropContract.Gov(&bind.CallOpts{Context: ctx})
 	if err != nil {
 		return "", fmt.Errorf("查询授权地址失败: %w", err)
 	}
 
-	// 5. 返回授权地址
+	// 4. 返回授权地址
 	return govAddr.Hex(), nil
 }
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-contracts/models"
+	"go-contracts/util"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	// ErrInsufficientBalance 代币余额不足
+	ErrInsufficientBalance = errors.New("代币余额不足")
+	// ErrInsufficientAllowance 授权额度不足
+	ErrInsufficientAllowance = errors.New("授权额度不足")
+)
+
+// checkERC20Balance 发送前检查账户余额，避免发送必然失败的交易
+func (s *serviceImpl) checkERC20Balance(ctx context.Context, contractAddress, account common.Address, value *big.Int) error {
+	balance, err := s.ethClient.ERC20Balance(ctx, contractAddress, account)
+	if err != nil {
+		return fmt.Errorf("查询余额失败: %w", err)
+	}
+	if balance.Cmp(value) < 0 {
+		return fmt.Errorf("%w: 账户 %s 余额 %s，需要 %s", ErrInsufficientBalance, account.Hex(), balance, value)
+	}
+	return nil
+}
+
+// checkERC20Allowance 发送前检查 owner 对 spender 的授权额度
+func (s *serviceImpl) checkERC20Allowance(ctx context.Context, contractAddress, owner, spender common.Address, value *big.Int) error {
+	allowance, err := s.ethClient.ERC20Allowance(ctx, contractAddress, owner, spender)
+	if err != nil {
+		return fmt.Errorf("查询授权额度失败: %w", err)
+	}
+	if allowance.Cmp(value) < 0 {
+		return fmt.Errorf("%w: %s 对 %s 的授权额度 %s，需要 %s", ErrInsufficientAllowance, owner.Hex(), spender.Hex(), allowance, value)
+	}
+	return nil
+}
+
+// recordERC20Transaction 将已发送的交易记录到 erc20_transactions，索引到链上日志后由索引结果替换
+// 只记录索引服务索引的合约，其他合约的交易不会被替换，其状态通过已发送交易记录查询
+// Gas 价格在打包前未知（EIP-1559 交易只有费用上限），由索引结果按回执中的实际价格补全
+// 交易已经发出，记录失败只打印日志
+func (s *serviceImpl) recordERC20Transaction(tx *types.Transaction, txType string, contractAddress, from, to common.Address, value *big.Int) {
+	if s.db == nil || !s.indexedERC20[contractAddress] {
+		return
+	}
+	record := &models.ERC20Transaction{
+		TxHash:          tx.Hash().Hex(),
+		From:            from.Hex(),
+		To:              to.Hex(),
+		ContractAddress: contractAddress.Hex(),
+		Amount:          value.String(),
+		TransactionType: txType,
+	}
+	if err := s.db.SavePendingERC20Transaction(record); err != nil {
+		util.Log.Error("记录ERC20交易失败", "txHash", record.TxHash, "error", err)
+	}
+}
+
+// parse 解析授权参数：合约地址、被授权方和授权金额（可以为 0，用于撤销授权）
+func (p ERC20ApproveParams) parse() (contractAddress, spender common.Address, value *big.Int, err error) {
+	if contractAddress, err = parseAddress("合约", p.ContractAddress); err != nil {
+		return
+	}
+	if spender, err = parseAddress("被授权方", p.Spender); err != nil {
+		return
+	}
+	value, err = parseAmount(p.Value)
+	return
+}
+
+// parse 解析转账参数：合约地址、接收方和转账金额
+func (p ERC20TransferParams) parse() (contractAddress, to common.Address, value *big.Int, err error) {
+	if contractAddress, err = parseAddress("合约", p.ContractAddress); err != nil {
+		return
+	}
+	if to, err = parseAddress("接收方", p.To); err != nil {
+		return
+	}
+	value, err = parsePositiveAmount(p.Value)
+	return
+}
+
+// parse 解析授权转账参数：合约地址、发送方、接收方和转账金额
+func (p ERC20TransferFromParams) parse() (contractAddress, from, to common.Address, value *big.Int, err error) {
+	if contractAddress, err = parseAddress("合约", p.ContractAddress); err != nil {
+		return
+	}
+	if from, err = parseAddress("发送方", p.From); err != nil {
+		return
+	}
+	if to, err = parseAddress("接收方", p.To); err != nil {
+		return
+	}
+	value, err = parsePositiveAmount(p.Value)
+	return
+}
+
+// parseAddress 解析地址参数，name 用于错误提示
+func parseAddress(name, value string) (common.Address, error) {
+	if !common.IsHexAddress(value) {
+		return common.Address{}, fmt.Errorf("无效的%s地址: %s", name, value)
+	}
+	return common.HexToAddress(value), nil
+}
+
+// parseAmount 解析十进制金额参数，不允许负数
+func parseAmount(value string) (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, fmt.Errorf("无效的金额格式: %s", value)
+	}
+	return amount, nil
+}
+
+// parsePositiveAmount 解析十进制金额参数，金额必须大于 0
+func parsePositiveAmount(value string) (*big.Int, error) {
+	amount, err := parseAmount(value)
+	if err != nil {
+		return nil, err
+	}
+	if amount.Sign() == 0 {
+		return nil, fmt.Errorf("金额必须大于0")
+	}
+	return amount, nil
+}
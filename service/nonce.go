@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-contracts/models"
+	"go-contracts/util"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// nonceStore 在途 nonce 的持久化（由 database.DB 实现）
+type nonceStore interface {
+	ListNonceReservations(address string) ([]*models.NonceReservation, error)
+	ReserveNonce(address string, nonce uint64) error
+	MarkNonceSent(address string, nonce uint64, txHash string) error
+	ReleaseNonce(address string, nonce uint64) error
+	PruneNonces(address string, below uint64) error
+}
+
+// nonceClient nonce 管理需要的节点接口
+type nonceClient interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+}
+
+// nonceResyncInterval 定期与节点重新同步的间隔，发现被节点丢弃的交易留下的空洞
+const nonceResyncInterval = time.Minute
+
+// nonceErrors 节点返回的 nonce 相关错误，出现时需要与节点重新同步
+var nonceErrors = []string{
+	"nonce too low",
+	"nonce too high",
+	"already known",
+	"known transaction",
+	"replacement transaction underpriced",
+}
+
+// nonceManager 单个签名账户的 nonce 分配器
+// 在锁内分配 nonce，并发发送的交易不会拿到相同的 nonce；在途 nonce 持久化到数据库，重启后不会重复使用
+type nonceManager struct {
+	mu      sync.Mutex
+	client  nonceClient
+	store   nonceStore
+	address common.Address
+
+	synced      bool            // 是否已与节点同步（首次使用或 nonce 出错后需要重新同步）
+	syncedAt    time.Time       // 最近一次同步的时间
+	next        uint64          // 下一个新分配的 nonce
+	released    []uint64        // 已分配但未使用的 nonce（升序），优先重新分配以填补空洞
+	outstanding map[uint64]bool // 本进程已分配、尚未 commit 或 release 的 nonce（正在发送），同步时不视为空洞
+}
+
+func newNonceManager(client nonceClient, store nonceStore, address common.Address) *nonceManager {
+	return &nonceManager{client: client, store: store, address: address, outstanding: make(map[uint64]bool)}
+}
+
+// acquire 分配一个 nonce，交易发送后必须调用 commit 或 release
+func (m *nonceManager) acquire(ctx context.Context) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// 1. 首次使用、出错后或超过同步间隔时与节点重新同步，否则只跟进节点的 pending nonce
+	if !m.synced || time.Since(m.syncedAt) >= nonceResyncInterval {
+		if err := m.sync(ctx); err != nil {
+			if !m.synced {
+				return 0, err
+			}
+			util.Log.Warn("定期同步nonce失败，使用本地计数", "address", m.address.Hex(), "error", err)
+		}
+	} else if err := m.catchUp(ctx); err != nil {
+		util.Log.Warn("查询pending nonce失败，使用本地计数", "address", m.address.Hex(), "error", err)
+	}
+
+	// 2. 优先分配空洞中的 nonce
+	var nonce uint64
+	if len(m.released) > 0 {
+		nonce, m.released = m.released[0], m.released[1:]
+	} else {
+		nonce = m.next
+		m.next++
+	}
+
+	// 3. 持久化在途 nonce
+	if err := m.store.ReserveNonce(m.address.Hex(), nonce); err != nil {
+		m.giveBack(nonce)
+		return 0, err
+	}
+	m.outstanding[nonce] = true
+	return nonce, nil
+}
+
+// commit 记录使用该 nonce 发送成功的交易
+func (m *nonceManager) commit(nonce uint64, txHash common.Hash) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.outstanding, nonce)
+	if err := m.store.MarkNonceSent(m.address.Hex(), nonce, txHash.Hex()); err != nil {
+		util.Log.Error("记录在途nonce失败", "address", m.address.Hex(), "nonce", nonce, "error", err)
+	}
+}
+
+// release 交易发送失败时归还 nonce；节点返回 nonce 相关错误时，下次分配前与节点重新同步
+func (m *nonceManager) release(nonce uint64, sendErr error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.outstanding, nonce)
+	if err := m.store.ReleaseNonce(m.address.Hex(), nonce); err != nil {
+		util.Log.Error("删除在途nonce失败", "address", m.address.Hex(), "nonce", nonce, "error", err)
+	}
+	if isNonceError(sendErr) {
+		util.Log.Warn("nonce错误，下次发送前重新同步", "address", m.address.Hex(), "nonce", nonce, "error", sendErr)
+		m.synced = false
+		return
+	}
+	m.giveBack(nonce)
+}
+
+// giveBack 归还未使用的 nonce：最后分配的直接回退计数，否则记为空洞
+func (m *nonceManager) giveBack(nonce uint64) {
+	if nonce+1 != m.next {
+		i, _ := slices.BinarySearch(m.released, nonce)
+		m.released = slices.Insert(m.released, i, nonce)
+		return
+	}
+	m.next--
+	for len(m.released) > 0 && m.released[len(m.released)-1]+1 == m.next {
+		m.released = m.released[:len(m.released)-1]
+		m.next--
+	}
+}
+
+// catchUp 节点的 pending nonce 超过本地计数时（同一账户在别处发送了交易）跟上节点，并清理节点已接收的在途记录
+func (m *nonceManager) catchUp(ctx context.Context) error {
+	pending, err := m.client.PendingNonceAt(ctx, m.address)
+	if err != nil {
+		return err
+	}
+	if pending > m.next {
+		util.Log.Warn("节点的nonce超过本地计数，可能有其他程序使用同一账户发送交易", "address", m.address.Hex(), "local", m.next, "pending", pending)
+		m.next = pending
+	}
+	for len(m.released) > 0 && m.released[0] < pending {
+		m.released = m.released[1:]
+	}
+	return m.store.PruneNonces(m.address.Hex(), pending)
+}
+
+// sync 与节点同步：取节点的 pending nonce 与持久化的在途 nonce 中较大者作为下一个 nonce，
+// 在途记录中节点不知道的 nonce（上次运行时未发出或交易已被丢弃）视为空洞，之后优先分配；
+// 本进程正在发送的 nonce 尚未写入交易哈希，不视为空洞
+func (m *nonceManager) sync(ctx context.Context) error {
+	// 1. 查询节点的 pending nonce，清理节点已接收的在途记录
+	pending, err := m.client.PendingNonceAt(ctx, m.address)
+	if err != nil {
+		return fmt.Errorf("查询nonce失败: %w", err)
+	}
+	if err := m.store.PruneNonces(m.address.Hex(), pending); err != nil {
+		return err
+	}
+	reservations, err := m.store.ListNonceReservations(m.address.Hex())
+	if err != nil {
+		return err
+	}
+
+	// 2. 正在发送的 nonce、已发出且节点仍能查到的交易占用其 nonce
+	m.next = pending
+	inUse := make(map[uint64]bool)
+	for nonce := range m.outstanding {
+		m.next = max(m.next, nonce+1)
+		inUse[nonce] = true
+	}
+	for _, r := range reservations {
+		m.next = max(m.next, r.Nonce+1)
+		if !inUse[r.Nonce] && r.TxHash != "" && m.known(ctx, common.HexToHash(r.TxHash)) {
+			inUse[r.Nonce] = true
+		}
+	}
+
+	// 3. 其余 nonce 是空洞，之后的交易会一直卡在交易池中，需要优先填补
+	m.released = nil
+	for n := pending; n < m.next; n++ {
+		if inUse[n] {
+			continue
+		}
+		m.released = append(m.released, n)
+		if err := m.store.ReleaseNonce(m.address.Hex(), n); err != nil {
+			return err
+		}
+	}
+	if len(m.released) > 0 {
+		util.Log.Warn("检测到nonce空洞，将优先重新分配", "address", m.address.Hex(), "pending", pending, "gaps", m.released)
+	}
+	m.synced = true
+	m.syncedAt = time.Now()
+	return nil
+}
+
+// known 节点是否知道该交易（在交易池中或已打包），查询出错时保守地视为已知，避免重复使用 nonce
+func (m *nonceManager) known(ctx context.Context, hash common.Hash) bool {
+	_, _, err := m.client.TransactionByHash(ctx, hash)
+	return !errors.Is(err, ethereum.NotFound)
+}
+
+// isNonceError 是否为 nonce 相关的发送错误
+func isNonceError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, s := range nonceErrors {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-contracts/models"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNonceClient 模拟节点：固定的 pending nonce 和已知交易
+type fakeNonceClient struct {
+	mu      sync.Mutex
+	pending uint64
+	known   map[common.Hash]bool
+}
+
+func (c *fakeNonceClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pending, nil
+}
+
+func (c *fakeNonceClient) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	if c.known[hash] {
+		return nil, true, nil
+	}
+	return nil, false, ethereum.NotFound
+}
+
+func (c *fakeNonceClient) setPending(n uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pending = n
+}
+
+// memNonceStore 内存中的在途 nonce 记录
+type memNonceStore struct {
+	mu   sync.Mutex
+	rows map[uint64]string
+}
+
+func newMemNonceStore() *memNonceStore {
+	return &memNonceStore{rows: make(map[uint64]string)}
+}
+
+func (s *memNonceStore) ListNonceReservations(address string) ([]*models.NonceReservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var list []*models.NonceReservation
+	for n, hash := range s.rows {
+		list = append(list, &models.NonceReservation{Address: address, Nonce: n, TxHash: hash})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Nonce < list[j].Nonce })
+	return list, nil
+}
+
+func (s *memNonceStore) ReserveNonce(address string, nonce uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.rows[nonce]; ok {
+		return errors.New("duplicate nonce")
+	}
+	s.rows[nonce] = ""
+	return nil
+}
+
+func (s *memNonceStore) MarkNonceSent(address string, nonce uint64, txHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rows[nonce] = txHash
+	return nil
+}
+
+func (s *memNonceStore) ReleaseNonce(address string, nonce uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.rows, nonce)
+	return nil
+}
+
+func (s *memNonceStore) PruneNonces(address string, below uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for n := range s.rows {
+		if n < below {
+			delete(s.rows, n)
+		}
+	}
+	return nil
+}
+
+var testNonceAccount = common.HexToAddress("0xabc")
+
+func TestNonceManager_Concurrent(t *testing.T) {
+	m := newNonceManager(&fakeNonceClient{pending: 5}, newMemNonceStore(), testNonceAccount)
+
+	const n = 50
+	nonces := make([]uint64, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			nonce, err := m.acquire(context.Background())
+			assert.NoError(t, err)
+			nonces[i] = nonce
+		}(i)
+	}
+	wg.Wait()
+
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+	for i, nonce := range nonces {
+		assert.Equal(t, uint64(5+i), nonce)
+	}
+}
+
+func TestNonceManager_Release(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeNonceClient{pending: 0}
+	m := newNonceManager(client, newMemNonceStore(), testNonceAccount)
+
+	for i := 0; i < 3; i++ {
+		_, err := m.acquire(ctx)
+		require.NoError(t, err)
+	}
+
+	// 中间的 nonce 发送失败留下空洞，下一次优先分配
+	m.release(1, errors.New("insufficient funds"))
+	nonce, err := m.acquire(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), nonce)
+
+	// 最后分配的 nonce 发送失败直接回退计数
+	m.release(2, errors.New("execution reverted"))
+	nonce, err = m.acquire(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), nonce)
+
+	// nonce 错误后重新同步节点的 pending nonce
+	client.setPending(10)
+	m.release(2, errors.New("nonce too low"))
+	nonce, err = m.acquire(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), nonce)
+}
+
+func TestNonceManager_Restart(t *testing.T) {
+	sent := common.HexToHash("0x01")
+	lost := common.HexToHash("0x02")
+	client := &fakeNonceClient{pending: 5, known: map[common.Hash]bool{sent: true}}
+
+	// 重启前：5 已发出（节点的 pending nonce 尚未反映），6 分配后未发出，7 已发出但交易丢失，8 已发出
+	store := newMemNonceStore()
+	store.rows[5] = sent.Hex()
+	store.rows[6] = ""
+	store.rows[7] = lost.Hex()
+	store.rows[8] = sent.Hex()
+
+	m := newNonceManager(client, store, testNonceAccount)
+	var got []uint64
+	for i := 0; i < 3; i++ {
+		nonce, err := m.acquire(context.Background())
+		require.NoError(t, err)
+		got = append(got, nonce)
+	}
+	// 先填补空洞 6、7，不重复使用 5、8
+	assert.Equal(t, []uint64{6, 7, 9}, got)
+}
+
+func TestNonceManager_Resync(t *testing.T) {
+	ctx := context.Background()
+	sent := common.HexToHash("0x01")
+	client := &fakeNonceClient{pending: 0, known: map[common.Hash]bool{sent: true}}
+	m := newNonceManager(client, newMemNonceStore(), testNonceAccount)
+
+	t.Run("正在发送的nonce不视为空洞", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			_, err := m.acquire(ctx)
+			require.NoError(t, err)
+		}
+		m.commit(0, sent)
+
+		// 2 发送失败触发重新同步，1 仍在发送中，不能重新分配
+		m.release(2, errors.New("nonce too low"))
+		nonce, err := m.acquire(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), nonce)
+		m.commit(1, sent)
+		m.commit(2, sent)
+	})
+
+	t.Run("定期同步时填补被丢弃交易的nonce", func(t *testing.T) {
+		m.commit(1, common.HexToHash("0x02")) // 1 的交易被节点丢弃
+		m.syncedAt = m.syncedAt.Add(-nonceResyncInterval)
+		nonce, err := m.acquire(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), nonce)
+	})
+}
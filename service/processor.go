@@ -4,24 +4,26 @@ import (
 	"context"
 	"go-contracts/config"
 	"go-contracts/database"
+	"go-contracts/models"
 	"go-contracts/synchronizer"
 	"go-contracts/util"
+	"gorm.io/gorm"
 	"sync/atomic"
 )
 
 // Processor 处理同步后的数据（如入库、过滤、转换）
 type Processor struct {
-	db          *database.DB            // 数据库连接
-	shutdown    context.CancelCauseFunc // 取消函数
-	stopped     atomic.Bool             // 停止状态标记
+	db           *database.DB                    // 数据库连接
+	shutdown     context.CancelCauseFunc         // 取消函数
+	stopped      atomic.Bool                     // 停止状态标记
 	blockChannel <-chan *synchronizer.BlockBatch // 区块数据通道
 }
 
 // NewProcessor 创建处理器实例
 func NewProcessor(cfg *config.IndexerConfig, db *database.DB, blockChannel <-chan *synchronizer.BlockBatch, shutdown context.CancelCauseFunc) (*Processor, error) {
 	return &Processor{
-		db:          db,
-		shutdown:    shutdown,
+		db:           db,
+		shutdown:     shutdown,
 		blockChannel: blockChannel,
 	}, nil
 }
@@ -52,21 +54,65 @@ func (p *Processor) processLoop(ctx context.Context) {
 				p.stopped.Store(true)
 				return
 			}
-			
+
+			// 发生链重组时先回滚分叉点之上的数据
+			if blockBatch.Reorg != nil {
+				if err := p.rollback(blockBatch.Reorg); err != nil {
+					util.Log.Error("链重组回滚失败", "err", err)
+					p.shutdown(err)
+					return
+				}
+				util.Log.Warn("链重组回滚完成", "fork_point", blockBatch.Reorg.ForkPoint)
+				continue
+			}
+
 			util.Log.Info("接收到区块批次", "count", len(blockBatch.Blocks))
-			
-			// 批量保存区块数据到数据库
-			if err := p.db.Create(blockBatch.Blocks).Error; err != nil {
+
+			// 批量保存区块数据，并在同一事务中推进同步检查点
+			if err := p.saveBatch(blockBatch); err != nil {
 				util.Log.Error("区块数据保存失败", "err", err)
 				p.shutdown(err)
 				return
 			}
-			
-			util.Log.Info("区块数据保存成功", "count", len(blockBatch.Blocks))
+
+			util.Log.Info("区块数据保存成功", "count", len(blockBatch.Blocks), "erc20_txs", len(blockBatch.ERC20Transactions))
 		}
 	}
 }
 
+// saveBatch 在同一事务中保存区块、ERC20 交易记录并更新检查点
+// 检查点只有在区块数据提交成功后才会前进
+func (p *Processor) saveBatch(blockBatch *synchronizer.BlockBatch) error {
+	if len(blockBatch.Blocks) == 0 {
+		return nil
+	}
+	lastBlock := blockBatch.Blocks[len(blockBatch.Blocks)-1]
+
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(blockBatch.Blocks).Error; err != nil {
+			return err
+		}
+		if err := database.SaveERC20Transactions(tx, blockBatch.ERC20Transactions); err != nil {
+			return err
+		}
+		return database.SaveCheckpoint(tx, models.CheckpointIndexer, lastBlock.BlockNumber)
+	})
+}
+
+// rollback 删除分叉点之上的孤块及派生数据，并将检查点回退到分叉点
+func (p *Processor) rollback(reorg *synchronizer.Reorg) error {
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		if err := database.RollbackAbove(tx, reorg.ForkPoint); err != nil {
+			return err
+		}
+		// 空投事件已随孤块删除，空投监听需从分叉点之后重新回填
+		if err := database.RewindCheckpoints(tx, models.CheckpointAirdropPrefix, reorg.ForkPoint); err != nil {
+			return err
+		}
+		return database.SaveCheckpoint(tx, models.CheckpointIndexer, reorg.ForkPoint)
+	})
+}
+
 // Close 停止处理器
 func (p *Processor) Close() error {
 	if p.stopped.CompareAndSwap(false, true) {
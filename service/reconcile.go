@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-contracts/contract"
+	"go-contracts/database"
+	"go-contracts/models"
+	"go-contracts/synchronizer/node"
+	"go-contracts/util"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// reconcilePageSize 每次从数据库读取并批量上链核对的余额条数
+const reconcilePageSize = 100
+
+// BalanceDrift 数据库余额与链上余额不一致的记录
+type BalanceDrift struct {
+	ContractAddress string `json:"contract_address"`
+	Account         string `json:"account"`
+	Stored          string `json:"stored"`  // 数据库中的余额
+	OnChain         string `json:"onchain"` // 链上余额
+}
+
+// ReconcileReport 余额核对结果
+type ReconcileReport struct {
+	BlockNumber uint64          `json:"block_number"` // 核对所用的区块高度（索引检查点）
+	Checked     int             `json:"checked"`      // 已核对的记录数
+	Drifts      []*BalanceDrift `json:"drifts"`       // 不一致的记录
+}
+
+// ReconcileBalances 抽查 erc20_balances 中的余额，与索引检查点高度的链上 balanceOf 结果比对
+// limit 为随机抽取核对的记录数（<= 0 表示按 ID 顺序核对全部）
+// 注意：余额只由起始区块之后的转账累计得到，起始区块晚于合约部署时出现偏差属于预期
+func ReconcileBalances(ctx context.Context, db *database.DB, ethClient node.EthClient, limit int) (*ReconcileReport, error) {
+	// 1. 以索引检查点作为核对高度，保证与数据库中的余额处于同一区块
+	checkpoint, err := db.GetCheckpoint(models.CheckpointIndexer)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint == nil {
+		return nil, fmt.Errorf("索引检查点不存在，请先运行索引服务")
+	}
+	report := &ReconcileReport{BlockNumber: checkpoint.BlockNumber}
+	blockNumber := new(big.Int).SetUint64(checkpoint.BlockNumber)
+
+	parsed, err := contract.Erc20MetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("解析ERC20 ABI失败: %w", err)
+	}
+	check := func(balances []*models.ERC20Balance) error {
+		return reconcilePage(ctx, ethClient, parsed, blockNumber, balances, report)
+	}
+
+	// 2. 指定数量时随机抽取（按 ID 取前 N 条只会覆盖最早的账户），分批核对
+	if limit > 0 {
+		balances, err := db.SampleERC20Balances(limit)
+		if err != nil {
+			return nil, err
+		}
+		for start := 0; start < len(balances); start += reconcilePageSize {
+			if err := check(balances[start:min(start+reconcilePageSize, len(balances))]); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		// 3. 核对全部时按 ID 分页读取
+		var afterID uint
+		for {
+			balances, err := db.ListERC20Balances(afterID, reconcilePageSize)
+			if err != nil {
+				return nil, err
+			}
+			if len(balances) == 0 {
+				break
+			}
+			afterID = balances[len(balances)-1].ID
+			if err := check(balances); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	util.Log.Info("ERC20余额核对完成", "block", report.BlockNumber, "checked", report.Checked, "drifts", len(report.Drifts))
+	return report, nil
+}
+
+// reconcilePage 批量调用 balanceOf 并与数据库中的余额比对，不一致的记录加入报告
+func reconcilePage(ctx context.Context, ethClient node.EthClient, parsed *abi.ABI, blockNumber *big.Int, balances []*models.ERC20Balance, report *ReconcileReport) error {
+	// 1. 批量查询链上余额
+	msgs := make([]ethereum.CallMsg, len(balances))
+	for i, balance := range balances {
+		data, err := parsed.Pack("balanceOf", common.HexToAddress(balance.Account))
+		if err != nil {
+			return fmt.Errorf("编码balanceOf调用失败: %w", err)
+		}
+		to := common.HexToAddress(balance.ContractAddress)
+		msgs[i] = ethereum.CallMsg{To: &to, Data: data}
+	}
+	results, err := ethClient.BatchCallContract(ctx, msgs, blockNumber)
+	if err != nil {
+		return fmt.Errorf("批量查询链上余额失败: %w", err)
+	}
+
+	// 2. 比对余额
+	for i, balance := range balances {
+		out, err := parsed.Unpack("balanceOf", results[i])
+		if err != nil || len(out) == 0 {
+			return fmt.Errorf("解析balanceOf结果失败（%s/%s）: %v", balance.ContractAddress, balance.Account, err)
+		}
+		onChain := out[0].(*big.Int)
+		if onChain.String() != balance.Balance {
+			drift := &BalanceDrift{
+				ContractAddress: balance.ContractAddress,
+				Account:         balance.Account,
+				Stored:          balance.Balance,
+				OnChain:         onChain.String(),
+			}
+			report.Drifts = append(report.Drifts, drift)
+			util.Log.Warn("ERC20余额不一致", "contract", drift.ContractAddress, "account", drift.Account,
+				"stored", drift.Stored, "onchain", drift.OnChain)
+		}
+	}
+	report.Checked += len(balances)
+	return nil
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-contracts/models"
+	"go-contracts/util"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	// ErrTransactionNotFound 交易不是由本服务发送的
+	ErrTransactionNotFound = errors.New("交易不存在")
+	// ErrTransactionNotPending 交易已打包或已进入最终状态，无法加速或取消
+	ErrTransactionNotPending = errors.New("交易不在待打包状态")
+)
+
+// recordSentTransaction 记录已发送的交易，交由交易跟踪服务确认最终状态
+// 交易已经发出，记录失败只打印日志
+func (s *serviceImpl) recordSentTransaction(tx *types.Transaction, kind string, from common.Address, replaces string) *models.SentTransaction {
+	record := &models.SentTransaction{
+		TxHash:   tx.Hash().Hex(),
+		Kind:     kind,
+		From:     from.Hex(),
+		Nonce:    tx.Nonce(),
+		Value:    tx.Value().String(),
+		Data:     hexutil.Encode(tx.Data()),
+		GasLimit: tx.Gas(),
+		Status:   models.TxStatusPending,
+		Replaces: replaces,
+	}
+	if tx.To() != nil {
+		record.To = tx.To().Hex()
+	}
+	if tx.Type() == types.LegacyTxType {
+		record.GasPrice = tx.GasPrice().String()
+	} else {
+		record.GasFeeCap = tx.GasFeeCap().String()
+		record.GasTipCap = tx.GasTipCap().String()
+	}
+	if s.db == nil {
+		return record
+	}
+	if err := s.db.SaveSentTransaction(record); err != nil {
+		util.Log.Error("记录已发送交易失败", "txHash", record.TxHash, "kind", kind, "error", err)
+	}
+	return record
+}
+
+// GetTransaction 查询已发送交易的状态，待确认的交易立即检查一次回执
+func (s *serviceImpl) GetTransaction(ctx context.Context, txHash string) (*models.SentTransaction, error) {
+	// 1. 查询交易记录
+	record, err := s.sentTransaction(txHash)
+	if err != nil {
+		return nil, err
+	}
+	if record.Final() || s.tracker == nil {
+		return record, nil
+	}
+
+	// 2. 检查回执，失败时返回已记录的状态
+	if err := s.tracker.check(ctx, record); err != nil {
+		util.Log.Warn("检查交易状态失败", "txHash", record.TxHash, "error", err)
+		return record, nil
+	}
+	return s.sentTransaction(record.TxHash)
+}
+
+// SpeedUpTransaction 加速待打包的交易：以相同 nonce 和内容、更高的费用重新签名发送，返回新交易
+func (s *serviceImpl) SpeedUpTransaction(ctx context.Context, txHash string) (*models.SentTransaction, error) {
+	// 1. 查询待加速的交易
+	record, err := s.pendingTransaction(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := new(big.Int).SetString(record.Value, 10)
+	if !ok {
+		return nil, fmt.Errorf("交易金额格式错误: %s", record.Value)
+	}
+
+	// 2. 以相同 nonce 和内容重新发送
+	return s.replaceTransaction(ctx, record, record.Kind, common.HexToAddress(record.To), value, common.FromHex(record.Data), record.GasLimit)
+}
+
+// CancelTransaction 取消待打包的交易：以相同 nonce、更高的费用向自己发送 0 金额交易，返回取消交易
+func (s *serviceImpl) CancelTransaction(ctx context.Context, txHash string) (*models.SentTransaction, error) {
+	// 1. 查询待取消的交易
+	record, err := s.pendingTransaction(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 以相同 nonce 发送 0 金额的自转账
+	return s.replaceTransaction(ctx, record, models.TxKindCancel, common.HexToAddress(record.From), new(big.Int), nil, params.TxGas)
+}
+
+// sentTransaction 按交易哈希查询已发送交易
+func (s *serviceImpl) sentTransaction(txHash string) (*models.SentTransaction, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("数据库未初始化")
+	}
+	hash, err := hexutil.Decode(txHash)
+	if err != nil || len(hash) != common.HashLength {
+		return nil, fmt.Errorf("无效的交易哈希: %s", txHash)
+	}
+	record, err := s.db.GetSentTransaction(common.BytesToHash(hash).Hex())
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, fmt.Errorf("%w: %s", ErrTransactionNotFound, txHash)
+	}
+	return record, nil
+}
+
+// pendingTransaction 查询可以加速或取消的交易：尚未打包，且由当前签名账户发送
+func (s *serviceImpl) pendingTransaction(ctx context.Context, txHash string) (*models.SentTransaction, error) {
+	record, err := s.sentTransaction(txHash)
+	if err != nil {
+		return nil, err
+	}
+	if record.Final() {
+		return nil, fmt.Errorf("%w: 交易状态为 %s", ErrTransactionNotPending, record.Status)
+	}
+	if _, err := s.ethClient.TransactionReceipt(ctx, common.HexToHash(record.TxHash)); err == nil {
+		return nil, fmt.Errorf("%w: 交易已打包，等待确认", ErrTransactionNotPending)
+	}
+	if s.signer == nil {
+		return nil, fmt.Errorf("未配置交易签名器")
+	}
+	if !strings.EqualFold(record.From, s.signer.Address().Hex()) {
+		return nil, fmt.Errorf("交易的发送账户 %s 不是当前签名账户 %s", record.From, s.signer.Address().Hex())
+	}
+	return record, nil
+}
+
+// replaceTransaction 使用被替换交易的 nonce 签名发送新交易
+// 费用在同一 nonce 已发送交易的最高费用上至少提高 12.5%（节点要求替换交易至少提高 10%），且不低于当前建议费用
+func (s *serviceImpl) replaceTransaction(ctx context.Context, record *models.SentTransaction, kind string, to common.Address, value *big.Int, data []byte, gas uint64) (*models.SentTransaction, error) {
+	// 1. 同一 nonce 已发送交易中的最高费用
+	siblings, err := s.db.ListSentTransactionsByNonce(record.From, record.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	feeCap, tip := highestFees(siblings)
+
+	// 2. 当前建议费用（交易选项的签名器已绑定链ID，签名时写入交易）
+	opts, err := s.newTransactOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 提高费用，超过配置上限时放弃
+	maxFee, maxTip := gweiToWei(s.txCfg.MaxFeeGwei), gweiToWei(s.txCfg.MaxTipGwei)
+	var inner types.TxData
+	if opts.GasFeeCap != nil {
+		newTip := replacementFee(tip, opts.GasTipCap)
+		newFeeCap := bigMax(replacementFee(feeCap, opts.GasFeeCap), newTip)
+		if maxTip != nil && newTip.Cmp(maxTip) > 0 {
+			return nil, fmt.Errorf("%w: 替换交易的小费 %s 超过上限 %s", ErrFeeTooHigh, newTip, maxTip)
+		}
+		if maxFee != nil && newFeeCap.Cmp(maxFee) > 0 {
+			return nil, fmt.Errorf("%w: 替换交易的费用 %s 超过上限 %s", ErrFeeTooHigh, newFeeCap, maxFee)
+		}
+		inner = &types.DynamicFeeTx{Nonce: record.Nonce, GasTipCap: newTip, GasFeeCap: newFeeCap, Gas: gas, To: &to, Value: value, Data: data}
+	} else {
+		gasPrice := replacementFee(feeCap, opts.GasPrice)
+		if maxFee != nil && gasPrice.Cmp(maxFee) > 0 {
+			return nil, fmt.Errorf("%w: 替换交易的Gas价格 %s 超过上限 %s", ErrFeeTooHigh, gasPrice, maxFee)
+		}
+		inner = &types.LegacyTx{Nonce: record.Nonce, GasPrice: gasPrice, Gas: gas, To: &to, Value: value, Data: data}
+	}
+
+	// 4. 签名并发送
+	tx, err := opts.Signer(opts.From, types.NewTx(inner))
+	if err != nil {
+		return nil, fmt.Errorf("签名替换交易失败: %w", err)
+	}
+	if err := s.ethClient.SendTransaction(ctx, tx); err != nil {
+		return nil, fmt.Errorf("发送替换交易失败: %w", err)
+	}
+	util.Log.Info("替换交易已发送", "kind", kind, "nonce", record.Nonce, "replaces", record.TxHash, "txHash", tx.Hash().Hex())
+
+	// 5. 在途 nonce 改为指向新交易（原交易已被节点从交易池中移除），记录新交易
+	if s.nonces != nil {
+		s.nonces.commit(record.Nonce, tx.Hash())
+	}
+	return s.recordSentTransaction(tx, kind, opts.From, record.TxHash), nil
+}
+
+// highestFees 同一 nonce 各交易的最高 feeCap 和小费，传统交易的 Gas 价格同时视为 feeCap 和小费
+func highestFees(txs []*models.SentTransaction) (*big.Int, *big.Int) {
+	feeCap, tip := new(big.Int), new(big.Int)
+	for _, t := range txs {
+		capStr, tipStr := t.GasFeeCap, t.GasTipCap
+		if capStr == "" {
+			capStr, tipStr = t.GasPrice, t.GasPrice
+		}
+		if v, ok := new(big.Int).SetString(capStr, 10); ok {
+			feeCap = bigMax(feeCap, v)
+		}
+		if v, ok := new(big.Int).SetString(tipStr, 10); ok {
+			tip = bigMax(tip, v)
+		}
+	}
+	return feeCap, tip
+}
+
+// replacementFee 替换交易的费用：原费用提高 12.5%（向上取整）与当前建议费用中的较大者
+func replacementFee(old, suggested *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(old, big.NewInt(9))
+	bumped.Add(bumped, big.NewInt(7))
+	bumped.Div(bumped, big.NewInt(8))
+	return bigMax(bumped, suggested)
+}
+
+func bigMax(a, b *big.Int) *big.Int {
+	if b != nil && a.Cmp(b) < 0 {
+		return b
+	}
+	return a
+}
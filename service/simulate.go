@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-contracts/config"
+	"go-contracts/contract"
+	"go-contracts/models"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// pendingBlock 查询交易池状态（pending 区块）时使用的区块号
+var pendingBlock = big.NewInt(int64(rpc.PendingBlockNumber))
+
+// SimulationResult 写操作的模拟执行结果（dry_run）：在 pending 区块上 eth_call 并估算 Gas，不发送交易
+type SimulationResult struct {
+	From          string   `json:"from"`                    // 签名账户
+	To            string   `json:"to"`                      // 调用的合约
+	Success       bool     `json:"success"`                 // 模拟执行成功，且余额、授权充足
+	RevertReason  string   `json:"revert_reason,omitempty"` // 模拟执行失败的原因
+	Transactions  int      `json:"transactions"`            // 需要发送的交易数（空投为切分后的分片数）
+	EstimatedGas  uint64   `json:"estimated_gas"`           // 估算的 Gas 用量总和
+	GasLimit      uint64   `json:"gas_limit"`               // 实际发送时的 Gas 上限总和（含安全余量）
+	MaxFeePerGas  string   `json:"max_fee_per_gas"`         // 每单位 Gas 的最高费用（EIP-1559 maxFeePerGas 或传统 Gas 价格）
+	EstimatedCost string   `json:"estimated_cost"`          // 预计交易费用：EstimatedGas * MaxFeePerGas（wei）
+	MaxCost       string   `json:"max_cost"`                // 最高交易费用：GasLimit * MaxFeePerGas（wei），发送时账户余额需覆盖
+	TotalAmount   string   `json:"total_amount"`            // 转出（授权时为授权）的代币或 BNB 总额
+	Value         string   `json:"value"`                   // 交易携带的 BNB 总额
+	Balance       string   `json:"balance"`                 // 签名账户的 BNB 余额
+	TokenBalance  string   `json:"token_balance,omitempty"` // 转出方的代币余额
+	Allowance     string   `json:"allowance,omitempty"`     // 转出方对调用方的授权额度
+	Problems      []string `json:"problems"`                // 余额不足、授权不足等问题
+
+	from   common.Address
+	maxFee *big.Int
+	value  *big.Int
+}
+
+func newSimulationResult(auth *bind.TransactOpts, to common.Address, totalAmount *big.Int) *SimulationResult {
+	maxFee := auth.GasFeeCap
+	if maxFee == nil {
+		maxFee = auth.GasPrice
+	}
+	return &SimulationResult{
+		From:         auth.From.Hex(),
+		To:           to.Hex(),
+		MaxFeePerGas: maxFee.String(),
+		TotalAmount:  totalAmount.String(),
+		Problems:     []string{},
+		from:         auth.From,
+		maxFee:       maxFee,
+		value:        new(big.Int),
+	}
+}
+
+// revert 执行回滚的错误记录为 revert 原因，网络、限流等其他错误原样返回
+func (r *SimulationResult) revert(err error) error {
+	if !isExecutionReverted(err) {
+		return err
+	}
+	r.RevertReason = decodeRevert(err)
+	return nil
+}
+
+// isExecutionReverted 判断节点返回的是否为执行回滚错误：错误码 3、带 revert 数据，
+// 或没有 revert 数据的 "execution reverted"（合约 revert() 不带原因时节点返回 -32000）
+func isExecutionReverted(err error) bool {
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+	if rpcErr.ErrorCode() == 3 {
+		return true
+	}
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok && data != "" {
+			return true
+		}
+	}
+	return strings.Contains(rpcErr.Error(), "execution reverted")
+}
+
+// require 现有金额不足时记录问题
+func (r *SimulationResult) require(name string, have, need *big.Int) {
+	if have.Cmp(need) < 0 {
+		r.Problems = append(r.Problems, fmt.Sprintf("%s不足: 现有 %s，需要 %s", name, have, need))
+	}
+}
+
+// maxCost 最高交易费用
+func (r *SimulationResult) maxCost() *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(r.GasLimit), r.maxFee)
+}
+
+// finish 汇总费用和结论
+func (r *SimulationResult) finish() *SimulationResult {
+	r.EstimatedCost = new(big.Int).Mul(new(big.Int).SetUint64(r.EstimatedGas), r.maxFee).String()
+	r.MaxCost = r.maxCost().String()
+	r.Value = r.value.String()
+	r.Success = r.RevertReason == "" && len(r.Problems) == 0
+	return r
+}
+
+// SimulateAirdropBnb 模拟BNB空投
+func (s *serviceImpl) SimulateAirdropBnb(ctx context.Context, params AirdropParams) (*SimulationResult, error) {
+	return s.simulateAirdrop(ctx, models.AirdropTypeBNB, params)
+}
+
+// SimulateAirdropERC20 模拟ERC20空投
+func (s *serviceImpl) SimulateAirdropERC20(ctx context.Context, params AirdropParams) (*SimulationResult, error) {
+	return s.simulateAirdrop(ctx, models.AirdropTypeERC20, params)
+}
+
+// simulateAirdrop 按实际发送的方式切分分片并逐个模拟执行，检查签名账户的 BNB 余额，
+// ERC20 空投还检查签名账户的代币余额及其对空投合约的授权额度
+func (s *serviceImpl) simulateAirdrop(ctx context.Context, eventType string, params AirdropParams) (*SimulationResult, error) {
+	// 1. 解析接收者地址和金额
+	recipients, amounts, err := parseAirdropParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 创建交易选项（签名账户与交易费用，不会请求签名）
+	auth, err := s.newTransactOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	contractAddress := common.HexToAddress(config.AIRDROP_CONTRACT_ADDRESS)
+	result := newSimulationResult(auth, contractAddress, sumAmounts(amounts))
+
+	// 3. 逐个分片模拟执行
+	if err := s.simulateAirdropChunks(ctx, result, eventType, contractAddress, recipients, amounts); err != nil {
+		return nil, err
+	}
+
+	// 4. 检查 BNB 余额（交易费用与转账金额）
+	if err := s.checkSimulationBalance(ctx, result); err != nil {
+		return nil, err
+	}
+
+	// 5. ERC20 空投检查代币余额和授权额度
+	if eventType == models.AirdropTypeERC20 {
+		airdropContract, err := contract.NewAirdropCaller(contractAddress, s.ethClient)
+		if err != nil {
+			return nil, fmt.Errorf("创建空投合约实例失败: %w", err)
+		}
+		token, err := airdropContract.Token(&bind.CallOpts{Context: ctx})
+		if err != nil {
+			return nil, fmt.Errorf("查询空投代币地址失败: %w", err)
+		}
+		if err := s.checkSimulationToken(ctx, result, token, auth.From, &contractAddress, sumAmounts(amounts)); err != nil {
+			return nil, err
+		}
+	}
+	return result.finish(), nil
+}
+
+// simulateAirdropChunks 与 sendAirdrop 相同地切分分片，逐个在 pending 区块上模拟执行并估算 Gas，遇到失败的分片即停止
+func (s *serviceImpl) simulateAirdropChunks(ctx context.Context, result *SimulationResult, eventType string, contractAddress common.Address, recipients []common.Address, amounts []*big.Int) error {
+	// 1. 估算 Gas 并按区块 Gas 上限切分
+	gas, err := s.estimateAirdropGas(ctx, result.from, contractAddress, eventType, recipients, amounts)
+	if err != nil {
+		return result.revert(err)
+	}
+	head, err := s.ethClient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("查询最新区块失败: %w", err)
+	}
+	sizes, err := planAirdropChunks(len(recipients), gas, head.GasLimit/airdropChunkGasShare)
+	if err != nil {
+		return err
+	}
+
+	// 2. 逐个分片模拟执行
+	parsed, err := contract.AirdropMetaData.GetAbi()
+	if err != nil {
+		return fmt.Errorf("解析空投合约ABI失败: %w", err)
+	}
+	offset := 0
+	for i, size := range sizes {
+		lo, hi := offset, offset+size
+		offset = hi
+
+		data, err := parsed.Pack(airdropMethods[eventType], recipients[lo:hi], amounts[lo:hi])
+		if err != nil {
+			return fmt.Errorf("编码空投调用失败: %w", err)
+		}
+		msg := ethereum.CallMsg{From: result.from, To: &contractAddress, Data: data}
+		if eventType == models.AirdropTypeBNB {
+			msg.Value = sumAmounts(amounts[lo:hi])
+		}
+		if err := s.simulateCall(ctx, result, msg, 0); err != nil {
+			return err
+		}
+		if result.RevertReason != "" {
+			result.RevertReason = fmt.Sprintf("分片 %d/%d: %s", i+1, len(sizes), result.RevertReason)
+			return nil
+		}
+	}
+	return nil
+}
+
+// SimulateERC20Approve 模拟授权
+func (s *serviceImpl) SimulateERC20Approve(ctx context.Context, params ERC20ApproveParams) (*SimulationResult, error) {
+	// 1. 解析参数
+	contractAddress, spender, value, err := params.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 模拟执行并检查 BNB 余额
+	result, err := s.simulateERC20(ctx, contractAddress, value, "approve", spender, value)
+	if err != nil {
+		return nil, err
+	}
+	return result.finish(), nil
+}
+
+// SimulateERC20Transfer 模拟转账，检查签名账户的代币余额
+func (s *serviceImpl) SimulateERC20Transfer(ctx context.Context, params ERC20TransferParams) (*SimulationResult, error) {
+	// 1. 解析参数
+	contractAddress, to, value, err := params.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 模拟执行并检查 BNB 余额
+	result, err := s.simulateERC20(ctx, contractAddress, value, "transfer", to, value)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 检查签名账户的代币余额
+	if err := s.checkSimulationToken(ctx, result, contractAddress, result.from, nil, value); err != nil {
+		return nil, err
+	}
+	return result.finish(), nil
+}
+
+// SimulateERC20TransferFrom 模拟授权转账，检查发送方的代币余额及其对签名账户的授权额度
+func (s *serviceImpl) SimulateERC20TransferFrom(ctx context.Context, params ERC20TransferFromParams) (*SimulationResult, error) {
+	// 1. 解析参数
+	contractAddress, from, to, value, err := params.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 模拟执行并检查 BNB 余额
+	result, err := s.simulateERC20(ctx, contractAddress, value, "transferFrom", from, to, value)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 检查发送方的代币余额及授权额度
+	if err := s.checkSimulationToken(ctx, result, contractAddress, from, &result.from, value); err != nil {
+		return nil, err
+	}
+	return result.finish(), nil
+}
+
+// simulateERC20 编码 ERC20 方法调用，在 pending 区块上模拟执行并检查签名账户的 BNB 余额
+func (s *serviceImpl) simulateERC20(ctx context.Context, contractAddress common.Address, amount *big.Int, method string, args ...interface{}) (*SimulationResult, error) {
+	// 1. 创建交易选项（签名账户与交易费用，不会请求签名）
+	auth, err := s.newTransactOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := newSimulationResult(auth, contractAddress, amount)
+
+	// 2. 编码调用
+	parsed, err := contract.Erc20MetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("解析ERC20合约ABI失败: %w", err)
+	}
+	data, err := parsed.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("编码%s调用失败: %w", method, err)
+	}
+
+	// 3. 模拟执行并检查 BNB 余额
+	msg := ethereum.CallMsg{From: auth.From, To: &contractAddress, Data: data}
+	if err := s.simulateCall(ctx, result, msg, 0); err != nil {
+		return nil, err
+	}
+	if err := s.checkSimulationBalance(ctx, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// simulateCall 在 pending 区块上执行调用并估算 Gas，执行失败时记录 revert 原因
+// gasLimit 为实际发送时使用的 Gas 上限，为 0 时使用估算值加安全余量
+func (s *serviceImpl) simulateCall(ctx context.Context, result *SimulationResult, msg ethereum.CallMsg, gasLimit uint64) error {
+	if _, err := s.ethClient.PendingCallContract(ctx, msg); err != nil {
+		return result.revert(err)
+	}
+	estimated, err := s.ethClient.PendingEstimateGas(ctx, msg)
+	if err != nil {
+		return result.revert(err)
+	}
+	if gasLimit == 0 {
+		gasLimit = s.withGasMargin(estimated)
+	}
+	if gasLimit < estimated {
+		result.Problems = append(result.Problems, fmt.Sprintf("第 %d 笔交易的Gas上限 %d 低于估算值 %d", result.Transactions+1, gasLimit, estimated))
+	}
+
+	result.Transactions++
+	result.EstimatedGas += estimated
+	result.GasLimit += gasLimit
+	if msg.Value != nil {
+		result.value.Add(result.value, msg.Value)
+	}
+	return nil
+}
+
+// checkSimulationBalance 检查签名账户的 BNB 余额是否覆盖最高交易费用和转账金额
+func (s *serviceImpl) checkSimulationBalance(ctx context.Context, result *SimulationResult) error {
+	balance, err := s.ethClient.BalanceAt(ctx, result.from, pendingBlock)
+	if err != nil {
+		return fmt.Errorf("查询BNB余额失败: %w", err)
+	}
+	result.Balance = balance.String()
+	result.require("BNB余额", balance, new(big.Int).Add(result.maxCost(), result.value))
+	return nil
+}
+
+// checkSimulationToken 检查 owner 的代币余额，spender 不为 nil 时同时检查 owner 对 spender 的授权额度
+func (s *serviceImpl) checkSimulationToken(ctx context.Context, result *SimulationResult, token, owner common.Address, spender *common.Address, amount *big.Int) error {
+	balance, err := s.ethClient.ERC20Balance(ctx, token, owner)
+	if err != nil {
+		return fmt.Errorf("查询余额失败: %w", err)
+	}
+	result.TokenBalance = balance.String()
+	result.require("代币余额", balance, amount)
+	if spender == nil {
+		return nil
+	}
+
+	allowance, err := s.ethClient.ERC20Allowance(ctx, token, owner, *spender)
+	if err != nil {
+		return fmt.Errorf("查询授权额度失败: %w", err)
+	}
+	result.Allowance = allowance.String()
+	result.require("授权额度", allowance, amount)
+	return nil
+}
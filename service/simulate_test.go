@@ -0,0 +1,68 @@
+package service
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rpcError 模拟节点返回的其他 JSON-RPC 错误
+type rpcError struct {
+	code int
+	msg  string
+}
+
+func (e *rpcError) Error() string  { return e.msg }
+func (e *rpcError) ErrorCode() int { return e.code }
+
+func TestSimulationResult(t *testing.T) {
+	auth := &bind.TransactOpts{From: common.HexToAddress("0xabc"), GasFeeCap: big.NewInt(10), GasTipCap: big.NewInt(1)}
+	contractAddress := common.HexToAddress("0xdef")
+
+	t.Run("费用与余额", func(t *testing.T) {
+		result := newSimulationResult(auth, contractAddress, big.NewInt(500))
+		result.Transactions, result.EstimatedGas, result.GasLimit = 2, 100, 120
+		result.value.SetInt64(500)
+		result.require("BNB余额", big.NewInt(1600), new(big.Int).Add(result.maxCost(), result.value))
+		result.finish()
+
+		assert.Equal(t, "1000", result.EstimatedCost)
+		assert.Equal(t, "1200", result.MaxCost)
+		assert.Equal(t, "500", result.Value)
+		assert.Equal(t, []string{"BNB余额不足: 现有 1600，需要 1700"}, result.Problems)
+		assert.False(t, result.Success)
+	})
+
+	t.Run("节点返回的执行错误记录为revert原因", func(t *testing.T) {
+		result := newSimulationResult(auth, contractAddress, big.NewInt(1))
+		require.NoError(t, result.revert(&revertError{revertData(t, "Error(string)", "string", "only gov")}))
+		assert.Equal(t, "only gov", result.RevertReason)
+		assert.False(t, result.finish().Success)
+	})
+
+	t.Run("不带原因的revert记录为revert原因", func(t *testing.T) {
+		result := newSimulationResult(auth, contractAddress, big.NewInt(1))
+		require.NoError(t, result.revert(&rpcError{-32000, "execution reverted"}))
+		assert.Equal(t, "execution reverted", result.RevertReason)
+	})
+
+	t.Run("限流等其他节点错误原样返回", func(t *testing.T) {
+		result := newSimulationResult(auth, contractAddress, big.NewInt(1))
+		err := &rpcError{-32005, "limit exceeded"}
+		assert.Equal(t, err, result.revert(err))
+		assert.Empty(t, result.RevertReason)
+	})
+
+	t.Run("网络错误原样返回", func(t *testing.T) {
+		result := newSimulationResult(auth, contractAddress, big.NewInt(1))
+		err := errors.New("connection refused")
+		assert.Equal(t, err, result.revert(err))
+		assert.Empty(t, result.RevertReason)
+		assert.True(t, result.finish().Success)
+	})
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-contracts/util"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ErrFeeTooHigh 交易费用超过配置上限
+var ErrFeeTooHigh = errors.New("交易费用超过上限")
+
+// defaultGasPrice 节点无法给出 Gas 价格建议时使用的默认值（10 Gwei）
+var defaultGasPrice = big.NewInt(10000000000)
+
+// newTransactOpts 创建交易选项：签名账户和交易费用（支持 London 的链使用 EIP-1559 动态费用交易）
+// GasLimit 留空，由 transact 估算，或由调用方自行设置
+func (s *serviceImpl) newTransactOpts(ctx context.Context) (*bind.TransactOpts, error) {
+	if s.signer == nil {
+		return nil, fmt.Errorf("未配置交易签名器")
+	}
+
+	// 1. 查询链ID
+	chainID, err := s.ethClient.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("查询链ID失败: %w", err)
+	}
+
+	// 2. 由签名器创建交易选项
+	opts, err := s.signer.TransactOpts(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 设置交易费用
+	if err := s.applyFees(ctx, opts); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// applyFees 根据最新区块设置交易费用，超过配置上限时放弃发送
+func (s *serviceImpl) applyFees(ctx context.Context, opts *bind.TransactOpts) error {
+	head, err := s.ethClient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("查询最新区块失败: %w", err)
+	}
+	maxFee, maxTip := gweiToWei(s.txCfg.MaxFeeGwei), gweiToWei(s.txCfg.MaxTipGwei)
+
+	// 未启用 London 的链（区块没有基础费用）使用传统 Gas 价格
+	if head.BaseFee == nil {
+		gasPrice, err := s.ethClient.SuggestGasPrice(ctx)
+		if err != nil {
+			gasPrice = defaultGasPrice
+			util.Log.Warn("获取Gas价格失败，使用默认值", "error", err)
+		}
+		if maxFee != nil && gasPrice.Cmp(maxFee) > 0 {
+			return fmt.Errorf("%w: Gas价格 %s 超过上限 %s", ErrFeeTooHigh, gasPrice, maxFee)
+		}
+		opts.GasPrice = gasPrice
+		return nil
+	}
+
+	tip, err := s.ethClient.SuggestGasTipCap(ctx)
+	if err != nil {
+		return fmt.Errorf("获取小费建议失败: %w", err)
+	}
+	feeCap, tip, err := dynamicFees(head.BaseFee, tip, maxFee, maxTip)
+	if err != nil {
+		return err
+	}
+	opts.GasFeeCap = feeCap
+	opts.GasTipCap = tip
+	return nil
+}
+
+// dynamicFees 计算 EIP-1559 费用：feeCap = 2 * baseFee + tip，可承受之后若干区块的基础费用上涨
+// feeCap 超过上限时，若当前基础费用加小费仍在上限内则截断到上限，否则放弃发送
+func dynamicFees(baseFee, tip, maxFee, maxTip *big.Int) (*big.Int, *big.Int, error) {
+	if maxTip != nil && tip.Cmp(maxTip) > 0 {
+		return nil, nil, fmt.Errorf("%w: 小费 %s 超过上限 %s", ErrFeeTooHigh, tip, maxTip)
+	}
+	feeCap := new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), tip)
+	if maxFee == nil || feeCap.Cmp(maxFee) <= 0 {
+		return feeCap, tip, nil
+	}
+	if new(big.Int).Add(baseFee, tip).Cmp(maxFee) > 0 {
+		return nil, nil, fmt.Errorf("%w: 基础费用 %s 加小费 %s 超过上限 %s", ErrFeeTooHigh, baseFee, tip, maxFee)
+	}
+	return new(big.Int).Set(maxFee), tip, nil
+}
+
+// transact 估算 Gas 并加上安全余量后发送交易，调用方已设置 GasLimit 时直接发送，kind 为记录的交易类型
+// 估算时以不签名、不发送（NoSend）的方式调用合约绑定方法，不会请求签名器
+func (s *serviceImpl) transact(opts *bind.TransactOpts, kind string, send func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	if opts.GasLimit == 0 {
+		dry := *opts
+		dry.NoSend = true
+		dry.Signer = func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) { return tx, nil }
+		tx, err := send(&dry)
+		if err != nil {
+			return nil, fmt.Errorf("估算Gas失败: %w", err)
+		}
+		opts.GasLimit = s.withGasMargin(tx.Gas())
+	}
+	return s.sendWithNonce(opts, kind, send)
+}
+
+// sendWithNonce 从 nonce 分配器取得 nonce 后发送交易，发送失败时归还
+// 发送成功的交易记录到 sent_transactions，由交易跟踪服务确认最终状态
+func (s *serviceImpl) sendWithNonce(opts *bind.TransactOpts, kind string, send func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	if s.nonces == nil {
+		tx, err := send(opts)
+		if err != nil {
+			return nil, err
+		}
+		s.recordSentTransaction(tx, kind, opts.From, "")
+		return tx, nil
+	}
+	nonce, err := s.nonces.acquire(opts.Context)
+	if err != nil {
+		return nil, fmt.Errorf("分配nonce失败: %w", err)
+	}
+	opts.Nonce = new(big.Int).SetUint64(nonce)
+	tx, err := send(opts)
+	if err != nil {
+		s.nonces.release(nonce, err)
+		return nil, err
+	}
+	s.nonces.commit(nonce, tx.Hash())
+	s.recordSentTransaction(tx, kind, opts.From, "")
+	return tx, nil
+}
+
+// withGasMargin 在估算的 Gas 上加上配置的安全余量
+func (s *serviceImpl) withGasMargin(gas uint64) uint64 {
+	return gas + gas*s.txCfg.GasMarginPercent/100
+}
+
+// gweiToWei 将 gwei 转换为 wei，0 或负数表示不限制，返回 nil
+func gweiToWei(gwei float64) *big.Int {
+	if gwei <= 0 {
+		return nil
+	}
+	wei, _ := new(big.Float).Mul(big.NewFloat(gwei), big.NewFloat(params.GWei)).Int(nil)
+	return wei
+}
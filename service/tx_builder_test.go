@@ -0,0 +1,41 @@
+package service
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDynamicFees(t *testing.T) {
+	gwei := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e9)) }
+
+	tests := []struct {
+		name    string
+		baseFee *big.Int
+		tip     *big.Int
+		maxFee  *big.Int
+		maxTip  *big.Int
+		feeCap  *big.Int
+		wantErr bool
+	}{
+		{"不限制", gwei(10), gwei(2), nil, nil, gwei(22), false},
+		{"上限之内", gwei(10), gwei(2), gwei(30), gwei(5), gwei(22), false},
+		{"超过上限时截断", gwei(10), gwei(2), gwei(15), nil, gwei(15), false},
+		{"基础费用超过上限", gwei(14), gwei(2), gwei(15), nil, nil, true},
+		{"小费超过上限", gwei(10), gwei(6), nil, gwei(5), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feeCap, tip, err := dynamicFees(tt.baseFee, tt.tip, tt.maxFee, tt.maxTip)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrFeeTooHigh)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.feeCap, feeCap)
+			assert.Equal(t, tt.tip, tip)
+		})
+	}
+}
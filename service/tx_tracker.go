@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-contracts/config"
+	"go-contracts/database"
+	"go-contracts/models"
+	"go-contracts/synchronizer/node"
+	"go-contracts/util"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	defaultTxPollInterval = 5 * time.Second
+	defaultTxDropTimeout  = 10 * time.Minute
+)
+
+// TxTracker 已发送交易的跟踪服务
+// 定期查询待确认交易的回执，达到确认数后记录为 mined 或 reverted（附解码后的 revert 原因），
+// 交易池中查不到且超时未打包的交易记录为 dropped
+// 实现了cycle.Service接口，与 API 服务共用数据库和区块链客户端，不负责关闭它们
+type TxTracker struct {
+	db            *database.DB
+	ethClient     node.EthClient
+	confirmations uint64        // 确认数（交易所在区块之上的区块数 + 1）
+	pollInterval  time.Duration // 轮询间隔
+	dropTimeout   time.Duration // 判定交易被丢弃的等待时间
+
+	cancel  context.CancelFunc
+	done    chan struct{}
+	stopped atomic.Bool
+}
+
+// NewTxTracker 创建交易跟踪服务
+func NewTxTracker(db *database.DB, ethClient node.EthClient, cfg config.TxConfig) *TxTracker {
+	t := &TxTracker{
+		db:            db,
+		ethClient:     ethClient,
+		confirmations: max(cfg.Confirmations, 1),
+		pollInterval:  time.Duration(cfg.PollInterval) * time.Second,
+		dropTimeout:   time.Duration(cfg.DropTimeout) * time.Second,
+	}
+	if t.pollInterval <= 0 {
+		t.pollInterval = defaultTxPollInterval
+	}
+	if t.dropTimeout <= 0 {
+		t.dropTimeout = defaultTxDropTimeout
+	}
+	return t
+}
+
+// Start 启动后台轮询
+func (t *TxTracker) Start(ctx context.Context) error {
+	if t.stopped.Load() || t.done != nil {
+		return nil
+	}
+	ctx, t.cancel = context.WithCancel(ctx)
+	t.done = make(chan struct{})
+
+	util.Log.Info("交易跟踪服务启动", "confirmations", t.confirmations, "interval", t.pollInterval, "dropTimeout", t.dropTimeout)
+	go t.loop(ctx)
+	return nil
+}
+
+// Stop 停止后台轮询并等待当前一轮检查结束
+func (t *TxTracker) Stop(ctx context.Context) error {
+	if !t.stopped.CompareAndSwap(false, true) || t.done == nil {
+		return nil
+	}
+	t.cancel()
+	select {
+	case <-t.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	util.Log.Info("交易跟踪服务已停止")
+	return nil
+}
+
+// Stopped 实现cycle.Service接口，返回服务是否已停止
+func (t *TxTracker) Stopped() bool {
+	return t.stopped.Load()
+}
+
+func (t *TxTracker) loop(ctx context.Context) {
+	defer close(t.done)
+
+	ticker := time.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+	for {
+		t.poll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll 检查所有待确认的交易，单笔交易检查失败不影响其他交易
+func (t *TxTracker) poll(ctx context.Context) {
+	pending, err := t.db.ListPendingSentTransactions()
+	if err != nil {
+		util.Log.Error("查询待确认交易失败", "error", err)
+		return
+	}
+	for _, tx := range pending {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := t.check(ctx, tx); err != nil {
+			util.Log.Warn("检查交易状态失败", "txHash", tx.TxHash, "error", err)
+		}
+	}
+}
+
+// check 检查一笔待确认交易的状态
+func (t *TxTracker) check(ctx context.Context, tx *models.SentTransaction) error {
+	hash := common.HexToHash(tx.TxHash)
+
+	// 1. 已打包：等待达到确认数
+	receipt, err := t.ethClient.TransactionReceipt(ctx, hash)
+	if err == nil {
+		return t.checkReceipt(ctx, tx, receipt)
+	}
+	if !errors.Is(err, ethereum.NotFound) {
+		return fmt.Errorf("查询交易回执失败: %w", err)
+	}
+
+	// 2. 之前已打包、现在查不到回执：所在区块被重组，重新等待打包
+	if tx.BlockNumber != 0 {
+		util.Log.Warn("交易所在区块被重组，重新等待打包", "txHash", tx.TxHash, "block", tx.BlockNumber)
+		if err := t.db.SetSentTransactionBlock(tx, 0); err != nil {
+			return err
+		}
+		tx.BlockNumber = 0
+	}
+
+	// 3. 同一 nonce 的其他交易（加速或取消交易）仍在等待时，由它们决定最终状态
+	siblings, err := t.db.ListSentTransactionsByNonce(tx.From, tx.Nonce)
+	if err != nil {
+		return err
+	}
+	replacement := latestPendingSibling(siblings, tx)
+
+	// 4. nonce 已被使用但不是本服务记录的交易，视为被丢弃
+	nonce, err := t.ethClient.NonceAt(ctx, common.HexToAddress(tx.From), nil)
+	if err != nil {
+		return fmt.Errorf("查询nonce失败: %w", err)
+	}
+	if nonce > tx.Nonce {
+		if replacement != nil {
+			return nil
+		}
+		util.Log.Warn("交易的nonce已被其他交易使用", "txHash", tx.TxHash, "nonce", tx.Nonce)
+		tx.Status = models.TxStatusDropped
+		return t.finalize(tx)
+	}
+
+	// 5. 交易池中查不到且超过等待时间，视为被丢弃
+	if _, _, err := t.ethClient.TransactionByHash(ctx, hash); !errors.Is(err, ethereum.NotFound) {
+		return err
+	}
+	if time.Since(tx.CreatedAt) < t.dropTimeout {
+		return nil
+	}
+	if replacement != nil {
+		return t.db.ReplaceSentTransaction(tx, replacement.TxHash)
+	}
+	tx.Status = models.TxStatusDropped
+	return t.finalize(tx)
+}
+
+// checkReceipt 交易已打包：未达到确认数时记录所在区块，达到后记录最终状态
+func (t *TxTracker) checkReceipt(ctx context.Context, tx *models.SentTransaction, receipt *types.Receipt) error {
+	head, err := t.ethClient.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("查询最新区块失败: %w", err)
+	}
+	blockNumber := receipt.BlockNumber.Uint64()
+	if head+1 < blockNumber+t.confirmations {
+		if tx.BlockNumber == blockNumber {
+			return nil
+		}
+		tx.BlockNumber = blockNumber
+		return t.db.SetSentTransactionBlock(tx, blockNumber)
+	}
+
+	tx.BlockNumber = blockNumber
+	tx.GasUsed = receipt.GasUsed
+	tx.Status = models.TxStatusMined
+	if receipt.Status == types.ReceiptStatusFailed {
+		tx.Status = models.TxStatusReverted
+		tx.RevertReason = t.revertReason(ctx, tx, receipt.BlockNumber)
+	}
+	return t.finalize(tx)
+}
+
+// finalize 记录交易最终状态，并更新同一 nonce 的其他交易及关联的空投、ERC20 记录
+func (t *TxTracker) finalize(tx *models.SentTransaction) error {
+	finalized, err := t.db.FinalizeSentTransaction(tx)
+	if err != nil {
+		return err
+	}
+	if finalized {
+		util.Log.Info("交易状态已确定", "txHash", tx.TxHash, "kind", tx.Kind, "nonce", tx.Nonce, "status", tx.Status, "block", tx.BlockNumber, "revertReason", tx.RevertReason)
+	}
+	return nil
+}
+
+// revertReason 在交易所在区块的父区块上重放交易，解码 revert 原因
+// 重放时的状态不包含同一区块中排在前面的交易，极少数情况下无法复现失败
+func (t *TxTracker) revertReason(ctx context.Context, tx *models.SentTransaction, blockNumber *big.Int) string {
+	to := common.HexToAddress(tx.To)
+	value, _ := new(big.Int).SetString(tx.Value, 10)
+	msg := ethereum.CallMsg{
+		From:  common.HexToAddress(tx.From),
+		To:    &to,
+		Gas:   tx.GasLimit,
+		Value: value,
+		Data:  common.FromHex(tx.Data),
+	}
+	_, err := t.ethClient.CallContract(ctx, msg, new(big.Int).Sub(blockNumber, big.NewInt(1)))
+	if err == nil {
+		return "未知（重放交易未复现失败，可能是 Gas 不足）"
+	}
+	return decodeRevert(err)
+}
+
+// decodeRevert 从 eth_call 的错误中解码 revert 原因（Error(string) 或 Panic(uint256)），无法解码时返回原始错误
+func decodeRevert(err error) string {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return err.Error()
+	}
+	data, ok := dataErr.ErrorData().(string)
+	if !ok || data == "" {
+		return err.Error()
+	}
+	reason, unpackErr := abi.UnpackRevert(common.FromHex(data))
+	if unpackErr != nil {
+		return fmt.Sprintf("%s（%s）", err.Error(), data) // 自定义错误，保留原始数据
+	}
+	return reason
+}
+
+// latestPendingSibling 同一 nonce 中最后发送的其他待确认交易
+func latestPendingSibling(siblings []*models.SentTransaction, tx *models.SentTransaction) *models.SentTransaction {
+	var latest *models.SentTransaction
+	for _, s := range siblings {
+		if s.ID != tx.ID && !s.Final() {
+			latest = s
+		}
+	}
+	return latest
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-contracts/models"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// revertError 模拟节点返回的带 revert 数据的错误（实现 rpc.Error 和 rpc.DataError）
+type revertError struct {
+	data string
+}
+
+func (e *revertError) Error() string          { return "execution reverted" }
+func (e *revertError) ErrorCode() int         { return 3 }
+func (e *revertError) ErrorData() interface{} { return e.data }
+
+// revertData 按 selector(signature) + ABI 编码参数构造 revert 数据
+func revertData(t *testing.T, signature, typ string, value interface{}) string {
+	argType, err := abi.NewType(typ, "", nil)
+	require.NoError(t, err)
+	packed, err := abi.Arguments{{Type: argType}}.Pack(value)
+	require.NoError(t, err)
+	return hexutil.Encode(append(crypto.Keccak256([]byte(signature))[:4], packed...))
+}
+
+func TestDecodeRevert(t *testing.T) {
+	custom := hexutil.Encode(crypto.Keccak256([]byte("NotGov()"))[:4])
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"Error(string)", &revertError{revertData(t, "Error(string)", "string", "only gov")}, "only gov"},
+		{"Panic(uint256)", &revertError{revertData(t, "Panic(uint256)", "uint256", big.NewInt(0x11))}, "arithmetic underflow or overflow"},
+		{"被包装的错误", fmt.Errorf("重试 3 次后仍失败: %w", &revertError{revertData(t, "Error(string)", "string", "paused")}), "paused"},
+		{"自定义错误保留原始数据", &revertError{custom}, fmt.Sprintf("execution reverted（%s）", custom)},
+		{"没有 revert 数据", errors.New("out of gas"), "out of gas"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, decodeRevert(tt.err))
+		})
+	}
+}
+
+func TestReplacementFees(t *testing.T) {
+	gwei := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e9)) }
+
+	t.Run("至少提高12.5%", func(t *testing.T) {
+		assert.Equal(t, big.NewInt(1125), replacementFee(big.NewInt(1000), big.NewInt(1)))
+		assert.Equal(t, big.NewInt(2), replacementFee(big.NewInt(1), nil)) // 向上取整
+		assert.Equal(t, gwei(30), replacementFee(gwei(8), gwei(30)))       // 当前建议费用更高
+	})
+
+	t.Run("同一nonce的最高费用", func(t *testing.T) {
+		txs := []*models.SentTransaction{
+			{GasFeeCap: gwei(20).String(), GasTipCap: gwei(2).String()},
+			{GasFeeCap: gwei(18).String(), GasTipCap: gwei(3).String()},
+			{GasPrice: gwei(19).String()},
+		}
+		feeCap, tip := highestFees(txs)
+		assert.Equal(t, gwei(20), feeCap)
+		assert.Equal(t, gwei(19), tip)
+	})
+}
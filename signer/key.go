@@ -0,0 +1,85 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// keySigner 使用内存中的私钥签名
+type keySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewKeySigner 从文件或环境变量读取十六进制私钥，keyFile 不为空时优先使用文件
+func NewKeySigner(keyEnv, keyFile string) (Signer, error) {
+	var hexKey string
+	switch {
+	case keyFile != "":
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取私钥文件失败: %w", err)
+		}
+		hexKey = string(data)
+	case keyEnv != "":
+		hexKey = os.Getenv(keyEnv)
+		if hexKey == "" {
+			return nil, fmt.Errorf("环境变量 %s 未设置私钥", keyEnv)
+		}
+	default:
+		return nil, fmt.Errorf("未配置私钥来源（key_env 或 key_file）")
+	}
+
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(hexKey), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("解析私钥失败: %w", err)
+	}
+	return newKeySigner(key), nil
+}
+
+// NewKeystoreSigner 使用密码文件解密 keystore JSON 文件
+func NewKeystoreSigner(keystoreFile, passphraseFile string) (Signer, error) {
+	keyJSON, err := os.ReadFile(keystoreFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取keystore文件失败: %w", err)
+	}
+	passphrase, err := os.ReadFile(passphraseFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取keystore密码文件失败: %w", err)
+	}
+
+	// 密码文件末尾的换行不属于密码
+	key, err := keystore.DecryptKey(keyJSON, strings.TrimRight(string(passphrase), "\r\n"))
+	if err != nil {
+		return nil, fmt.Errorf("解密keystore失败: %w", err)
+	}
+	return newKeySigner(key.PrivateKey), nil
+}
+
+func newKeySigner(key *ecdsa.PrivateKey) *keySigner {
+	return &keySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+// Address 签名账户地址
+func (k *keySigner) Address() common.Address {
+	return k.address
+}
+
+// TransactOpts 生成使用私钥签名的交易选项
+func (k *keySigner) TransactOpts(ctx context.Context, chainID *big.Int) (*bind.TransactOpts, error) {
+	opts, err := bind.NewKeyedTransactorWithChainID(k.key, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("创建交易选项失败: %w", err)
+	}
+	opts.Context = ctx
+	return opts, nil
+}
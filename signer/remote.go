@@ -0,0 +1,139 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// remoteSigner 通过 eth_signTransaction 请求远程签名服务（如 clef、节点解锁账户）签名
+type remoteSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+// signTxArgs eth_signTransaction 的交易参数
+type signTxArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *hexutil.Big    `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+}
+
+// signTxResult eth_signTransaction 的返回结果（geth、clef 返回对象，部分签名服务只返回原始交易）
+type signTxResult struct {
+	Raw hexutil.Bytes `json:"raw"`
+}
+
+// NewRemoteSigner 连接远程签名服务，address 为签名账户
+func NewRemoteSigner(ctx context.Context, url, address string) (Signer, error) {
+	if url == "" {
+		return nil, fmt.Errorf("未配置远程签名服务地址")
+	}
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("无效的签名账户地址: %s", address)
+	}
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("连接远程签名服务失败: %w", err)
+	}
+	return &remoteSigner{client: client, address: common.HexToAddress(address)}, nil
+}
+
+// Address 签名账户地址
+func (r *remoteSigner) Address() common.Address {
+	return r.address
+}
+
+// TransactOpts 生成由远程签名服务签名的交易选项
+func (r *remoteSigner) TransactOpts(ctx context.Context, chainID *big.Int) (*bind.TransactOpts, error) {
+	return &bind.TransactOpts{
+		From:    r.address,
+		Context: ctx,
+		Signer: func(from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if from != r.address {
+				return nil, bind.ErrNotAuthorized
+			}
+			return r.signTransaction(ctx, tx, chainID)
+		},
+	}, nil
+}
+
+// signTransaction 请求远程签名，并校验签名账户和交易内容未被改动
+func (r *remoteSigner) signTransaction(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	// 1. 构造请求参数
+	args := signTxArgs{
+		From:    r.address,
+		To:      tx.To(),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   (*hexutil.Big)(tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    tx.Data(),
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.Type() == types.DynamicFeeTxType {
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	} else {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	}
+
+	// 2. 请求签名
+	var result json.RawMessage
+	if err := r.client.CallContext(ctx, &result, "eth_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("远程签名失败: %w", err)
+	}
+	var raw hexutil.Bytes
+	if err := json.Unmarshal(result, &raw); err != nil {
+		var obj signTxResult
+		if err := json.Unmarshal(result, &obj); err != nil {
+			return nil, fmt.Errorf("解析远程签名结果失败: %w", err)
+		}
+		raw = obj.Raw
+	}
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("解析远程签名交易失败: %w", err)
+	}
+
+	// 3. 校验签名账户和交易内容
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return nil, fmt.Errorf("校验远程签名失败: %w", err)
+	}
+	if sender != r.address {
+		return nil, fmt.Errorf("远程签名账户不一致: 期望 %s，实际 %s", r.address.Hex(), sender.Hex())
+	}
+	if !sameTransaction(signed, tx) {
+		return nil, fmt.Errorf("远程签名的交易内容与请求不一致")
+	}
+	return signed, nil
+}
+
+// sameTransaction 比较签名前后交易的关键字段
+func sameTransaction(signed, tx *types.Transaction) bool {
+	if signed.Nonce() != tx.Nonce() || signed.Gas() != tx.Gas() || signed.Value().Cmp(tx.Value()) != 0 {
+		return false
+	}
+	if !bytes.Equal(signed.Data(), tx.Data()) {
+		return false
+	}
+	if signed.To() == nil || tx.To() == nil {
+		return signed.To() == tx.To()
+	}
+	return *signed.To() == *tx.To()
+}
@@ -0,0 +1,40 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"go-contracts/config"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 签名方式
+const (
+	TypeKey      = "key"      // 原始私钥（环境变量或文件）
+	TypeKeystore = "keystore" // 加密的 keystore JSON 文件 + 密码文件
+	TypeRemote   = "remote"   // 远程签名服务（eth_signTransaction）
+)
+
+// Signer 交易签名器，为合约绑定生成交易选项
+type Signer interface {
+	// Address 签名账户地址
+	Address() common.Address
+	// TransactOpts 生成指定链的交易选项，Signer 回调使用本签名器签名
+	TransactOpts(ctx context.Context, chainID *big.Int) (*bind.TransactOpts, error)
+}
+
+// New 根据配置创建签名器
+func New(ctx context.Context, cfg config.SignerConfig) (Signer, error) {
+	switch cfg.Type {
+	case TypeKey, "":
+		return NewKeySigner(cfg.KeyEnv, cfg.KeyFile)
+	case TypeKeystore:
+		return NewKeystoreSigner(cfg.KeystoreFile, cfg.PassphraseFile)
+	case TypeRemote:
+		return NewRemoteSigner(ctx, cfg.RemoteURL, cfg.Address)
+	default:
+		return nil, fmt.Errorf("不支持的签名方式: %s", cfg.Type)
+	}
+}
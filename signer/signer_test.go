@@ -0,0 +1,159 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testChainID = big.NewInt(97)
+
+// signAndCheck 使用签名器签名一笔测试交易，返回签名账户
+func signAndCheck(t *testing.T, s Signer) common.Address {
+	opts, err := s.TransactOpts(context.Background(), testChainID)
+	require.NoError(t, err)
+
+	to := common.HexToAddress("0x1")
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   testChainID,
+		Nonce:     7,
+		GasTipCap: big.NewInt(1e9),
+		GasFeeCap: big.NewInt(3e9),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(1),
+		Data:      []byte{0x01, 0x02},
+	})
+	signed, err := opts.Signer(opts.From, tx)
+	require.NoError(t, err)
+
+	sender, err := types.Sender(types.LatestSignerForChainID(testChainID), signed)
+	require.NoError(t, err)
+	assert.Equal(t, opts.From, sender)
+	assert.Equal(t, tx.Nonce(), signed.Nonce())
+	return sender
+}
+
+func TestKeySigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	hexKey := hex.EncodeToString(crypto.FromECDSA(key))
+
+	t.Run("环境变量", func(t *testing.T) {
+		t.Setenv("TEST_SIGNER_KEY", "0x"+hexKey)
+		s, err := NewKeySigner("TEST_SIGNER_KEY", "")
+		require.NoError(t, err)
+		assert.Equal(t, address, signAndCheck(t, s))
+	})
+
+	t.Run("文件", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "key")
+		require.NoError(t, os.WriteFile(file, []byte(hexKey+"\n"), 0600))
+		s, err := NewKeySigner("", file)
+		require.NoError(t, err)
+		assert.Equal(t, address, signAndCheck(t, s))
+	})
+
+	t.Run("环境变量未设置", func(t *testing.T) {
+		_, err := NewKeySigner("TEST_SIGNER_KEY_MISSING", "")
+		assert.Error(t, err)
+	})
+}
+
+func TestKeystoreSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	dir := t.TempDir()
+	account, err := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP).ImportECDSA(key, "secret")
+	require.NoError(t, err)
+	keystoreFile := account.URL.Path
+	passphraseFile := filepath.Join(dir, "passphrase")
+	require.NoError(t, os.WriteFile(passphraseFile, []byte("secret\n"), 0600))
+
+	s, err := NewKeystoreSigner(keystoreFile, passphraseFile)
+	require.NoError(t, err)
+	assert.Equal(t, address, signAndCheck(t, s))
+
+	require.NoError(t, os.WriteFile(passphraseFile, []byte("wrong"), 0600))
+	_, err = NewKeystoreSigner(keystoreFile, passphraseFile)
+	assert.Error(t, err)
+}
+
+// signService 模拟远程签名服务的 eth_signTransaction
+type signService struct {
+	key *ecdsa.PrivateKey
+}
+
+func (s *signService) SignTransaction(args signTxArgs) (*signTxResult, error) {
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   args.ChainID.ToInt(),
+		Nonce:     uint64(args.Nonce),
+		GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+		GasFeeCap: args.MaxFeePerGas.ToInt(),
+		Gas:       uint64(args.Gas),
+		To:        args.To,
+		Value:     args.Value.ToInt(),
+		Data:      args.Data,
+	})
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(args.ChainID.ToInt()), s.key)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &signTxResult{Raw: raw}, nil
+}
+
+// newSignServer 启动本地的远程签名服务
+func newSignServer(t *testing.T, key *ecdsa.PrivateKey) string {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", &signService{key: key}))
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+	t.Cleanup(server.Stop)
+	return ts.URL
+}
+
+func TestRemoteSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	t.Run("远程签名", func(t *testing.T) {
+		s, err := NewRemoteSigner(context.Background(), newSignServer(t, key), address.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, address, signAndCheck(t, s))
+	})
+
+	t.Run("签名账户不一致", func(t *testing.T) {
+		other, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		s, err := NewRemoteSigner(context.Background(), newSignServer(t, other), address.Hex())
+		require.NoError(t, err)
+
+		opts, err := s.TransactOpts(context.Background(), testChainID)
+		require.NoError(t, err)
+		to := common.HexToAddress("0x1")
+		tx := types.NewTx(&types.DynamicFeeTx{ChainID: testChainID, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(1), Gas: 21000, To: &to, Value: big.NewInt(0)})
+		_, err = opts.Signer(opts.From, tx)
+		assert.ErrorContains(t, err, "远程签名账户不一致")
+	})
+}
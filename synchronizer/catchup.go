@@ -0,0 +1,75 @@
+package synchronizer
+
+import (
+	"context"
+	"go-contracts/util"
+)
+
+// rangeResult 单个范围的拉取结果
+type rangeResult struct {
+	index int
+	batch *BlockBatch
+	err   error
+}
+
+// catchUp 追赶阶段：将 [lastBlockNum, head] 按 rangeSize 切分为多个范围，由有限数量的协程并发拉取
+// 范围在派发时按需生成，不预先构建完整列表；拉取结果按范围顺序依次发送到处理通道，保证处理器收到的区块严格有序
+func (s *Synchronizer) catchUp(ctx context.Context, head uint64) error {
+	total := int((head-s.lastBlockNum)/s.rangeSize + 1)
+	util.Log.Info("进入追赶阶段", "start", s.lastBlockNum, "head", head, "ranges", total, "workers", s.workers)
+
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// 同时在途的范围不超过 workers，结果通道容量足够，提前退出时协程不会阻塞
+	results := make(chan rangeResult, s.workers)
+	launched, inFlight := 0, 0
+	nextStart := s.lastBlockNum // 下一个待派发范围的起始区块
+	launch := func() {
+		index, start, end := launched, nextStart, min(nextStart+s.rangeSize-1, head)
+		launched++
+		inFlight++
+		nextStart = end + 1
+		go func() {
+			batch, err := s.fetchRange(fetchCtx, start, end, true)
+			results <- rangeResult{index: index, batch: batch, err: err}
+		}()
+	}
+	for launched < total && inFlight < s.workers {
+		launch()
+	}
+
+	// 乱序到达的结果暂存，按顺序发送
+	pending := make(map[int]rangeResult)
+	for next := 0; next < total; {
+		select {
+		case res := <-results:
+			inFlight--
+			pending[res.index] = res
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		for {
+			res, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			if res.err != nil {
+				return res.err
+			}
+			reorged, err := s.emitBatch(ctx, res.batch)
+			if err != nil || reorged {
+				return err
+			}
+			next++
+			if launched < total {
+				launch()
+			}
+		}
+	}
+
+	util.Log.Info("追赶阶段完成", "head", head)
+	return nil
+}
@@ -0,0 +1,136 @@
+package synchronizer
+
+import (
+	"context"
+	"fmt"
+	"go-contracts/contract"
+	"go-contracts/models"
+	"go-contracts/util"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// erc20Filterer 仅用于解析日志，与合约地址无关
+var erc20Filterer = func() *contract.Erc20Filterer {
+	filterer, err := contract.NewErc20Filterer(common.Address{}, nil)
+	if err != nil {
+		panic(err)
+	}
+	return filterer
+}()
+
+// erc20Topics Transfer 与 Approval 事件签名
+var erc20Topics = func() []common.Hash {
+	parsed, err := contract.Erc20MetaData.GetAbi()
+	if err != nil {
+		panic(err)
+	}
+	return []common.Hash{parsed.Events["Transfer"].ID, parsed.Events["Approval"].ID}
+}()
+
+// fetchERC20Transactions 拉取 [start, end] 范围内配置合约的 Transfer、Approval 日志
+// 并结合交易收据构造 ERC20 交易记录；日志所在区块哈希与 blocks 不一致时返回 stale = true
+func (s *Synchronizer) fetchERC20Transactions(ctx context.Context, blocks []*models.Block) (txs []*models.ERC20Transaction, stale bool, err error) {
+	if len(s.erc20Contracts) == 0 || len(blocks) == 0 {
+		return nil, false, nil
+	}
+	start, end := blocks[0].BlockNumber, blocks[len(blocks)-1].BlockNumber
+
+	// 1. 查询范围内的事件日志
+	logs, err := s.ethClient.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(start),
+		ToBlock:   new(big.Int).SetUint64(end),
+		Addresses: s.erc20Contracts,
+		Topics:    [][]common.Hash{erc20Topics},
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("查询ERC20事件日志失败（%d-%d）: %w", start, end, err)
+	}
+	if len(logs) == 0 {
+		return nil, false, nil
+	}
+
+	// 2. 校验日志与已拉取的区块属于同一条链
+	hashes := make(map[uint64]string, len(blocks))
+	for _, block := range blocks {
+		hashes[block.BlockNumber] = block.BlockHash
+	}
+	var txHashes []common.Hash
+	seen := make(map[common.Hash]bool)
+	for _, log := range logs {
+		if log.Removed || hashes[log.BlockNumber] != log.BlockHash.Hex() {
+			return nil, true, nil
+		}
+		if !seen[log.TxHash] {
+			seen[log.TxHash] = true
+			txHashes = append(txHashes, log.TxHash)
+		}
+	}
+
+	// 3. 批量获取交易收据（gas、状态）
+	receipts, err := s.ethClient.BatchTransactionReceipts(ctx, txHashes)
+	if err != nil {
+		return nil, false, fmt.Errorf("批量获取交易收据失败（%d-%d）: %w", start, end, err)
+	}
+	receiptByHash := make(map[common.Hash]*types.Receipt, len(receipts))
+	for _, receipt := range receipts {
+		receiptByHash[receipt.TxHash] = receipt
+	}
+
+	// 4. 解析日志，无法解析的日志（如 topic0 相同、参数全部 indexed 的 ERC721 Transfer）跳过
+	txs = make([]*models.ERC20Transaction, 0, len(logs))
+	for _, log := range logs {
+		tx, err := decodeERC20Log(log, receiptByHash[log.TxHash])
+		if err != nil {
+			util.Log.Warn("跳过无法解析的ERC20日志", "contract", log.Address.Hex(), "txHash", log.TxHash.Hex(), "logIndex", log.Index, "error", err)
+			continue
+		}
+		txs = append(txs, tx)
+	}
+	return txs, false, nil
+}
+
+// decodeERC20Log 将 Transfer / Approval 日志解析为 ERC20 交易记录
+func decodeERC20Log(log types.Log, receipt *types.Receipt) (*models.ERC20Transaction, error) {
+	tx := &models.ERC20Transaction{
+		TxHash:          log.TxHash.Hex(),
+		BlockHash:       log.BlockHash.Hex(),
+		BlockNumber:     log.BlockNumber,
+		LogIndex:        log.Index,
+		ContractAddress: log.Address.Hex(),
+	}
+	if len(log.Topics) == 0 {
+		return nil, fmt.Errorf("日志没有事件签名")
+	}
+
+	switch log.Topics[0] {
+	case erc20Topics[0]:
+		event, err := erc20Filterer.ParseTransfer(log)
+		if err != nil {
+			return nil, fmt.Errorf("解析Transfer事件失败（%s:%d）: %w", log.TxHash.Hex(), log.Index, err)
+		}
+		tx.From, tx.To, tx.Amount = event.From.Hex(), event.To.Hex(), event.Value.String()
+		tx.TransactionType = models.ERC20TypeTransfer
+	case erc20Topics[1]:
+		event, err := erc20Filterer.ParseApproval(log)
+		if err != nil {
+			return nil, fmt.Errorf("解析Approval事件失败（%s:%d）: %w", log.TxHash.Hex(), log.Index, err)
+		}
+		tx.From, tx.To, tx.Amount = event.Owner.Hex(), event.Spender.Hex(), event.Value.String()
+		tx.TransactionType = models.ERC20TypeApprove
+	default:
+		return nil, fmt.Errorf("未知的ERC20事件签名: %s", log.Topics[0].Hex())
+	}
+
+	if receipt != nil {
+		tx.GasUsed = receipt.GasUsed
+		tx.Status = receipt.Status == types.ReceiptStatusSuccessful
+		if receipt.EffectiveGasPrice != nil {
+			tx.GasPrice = receipt.EffectiveGasPrice.String()
+		}
+	}
+	return tx, nil
+}
@@ -0,0 +1,162 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// defaultBatchSize 单个 JSON-RPC 批量请求包含的最大调用数
+const defaultBatchSize = 100
+
+// BlockSummary 区块头及交易数量（eth_getBlockByNumber 不含交易详情时的返回）
+type BlockSummary struct {
+	Header  *types.Header
+	TxCount int
+}
+
+// batchCall 将调用按 batchSize 分组后以 JSON-RPC 批量请求发送
+// 每组请求单独经由连接池执行，失败时按连接池策略重试或切换节点
+func (p *Pool) batchCall(ctx context.Context, elems []rpc.BatchElem) error {
+	for start := 0; start < len(elems); start += p.batchSize {
+		end := start + p.batchSize
+		if end > len(elems) {
+			end = len(elems)
+		}
+		chunk := elems[start:end]
+		err := p.do(ctx, func(client *ethclient.Client) error {
+			if err := client.Client().BatchCallContext(ctx, chunk); err != nil {
+				return err
+			}
+			for _, elem := range chunk {
+				if elem.Error != nil {
+					return fmt.Errorf("%s 调用失败: %w", elem.Method, elem.Error)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BatchBlockSummaries 批量获取区块头及交易数量，结果与 numbers 顺序一致
+func (e *ethClientImpl) BatchBlockSummaries(ctx context.Context, numbers []uint64) ([]*BlockSummary, error) {
+	raws := make([]json.RawMessage, len(numbers))
+	elems := make([]rpc.BatchElem, len(numbers))
+	for i, n := range numbers {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getBlockByNumber",
+			Args:   []interface{}{hexutil.EncodeUint64(n), false},
+			Result: &raws[i],
+		}
+	}
+	if err := e.pool.batchCall(ctx, elems); err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*BlockSummary, len(numbers))
+	for i, raw := range raws {
+		if len(raw) == 0 || string(raw) == "null" {
+			return nil, fmt.Errorf("区块 %d: %w", numbers[i], ethereum.NotFound)
+		}
+		var header types.Header
+		if err := json.Unmarshal(raw, &header); err != nil {
+			return nil, fmt.Errorf("解析区块头失败（区块号: %d）: %w", numbers[i], err)
+		}
+		var body struct {
+			Transactions []common.Hash `json:"transactions"`
+		}
+		if err := json.Unmarshal(raw, &body); err != nil {
+			return nil, fmt.Errorf("解析区块交易列表失败（区块号: %d）: %w", numbers[i], err)
+		}
+		summaries[i] = &BlockSummary{Header: &header, TxCount: len(body.Transactions)}
+	}
+	return summaries, nil
+}
+
+// BatchTransactionReceipts 批量获取交易收据，结果与 hashes 顺序一致
+func (e *ethClientImpl) BatchTransactionReceipts(ctx context.Context, hashes []common.Hash) ([]*types.Receipt, error) {
+	receipts := make([]*types.Receipt, len(hashes))
+	elems := make([]rpc.BatchElem, len(hashes))
+	for i, hash := range hashes {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []interface{}{hash},
+			Result: &receipts[i],
+		}
+	}
+	if err := e.pool.batchCall(ctx, elems); err != nil {
+		return nil, err
+	}
+	for i, receipt := range receipts {
+		if receipt == nil {
+			return nil, fmt.Errorf("交易 %s: %w", hashes[i].Hex(), ethereum.NotFound)
+		}
+	}
+	return receipts, nil
+}
+
+// BatchCallContract 批量执行只读合约调用，结果与 msgs 顺序一致
+func (e *ethClientImpl) BatchCallContract(ctx context.Context, msgs []ethereum.CallMsg, blockNumber *big.Int) ([][]byte, error) {
+	results := make([]hexutil.Bytes, len(msgs))
+	elems := make([]rpc.BatchElem, len(msgs))
+	for i, msg := range msgs {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_call",
+			Args:   []interface{}{toCallArg(msg), toBlockNumArg(blockNumber)},
+			Result: &results[i],
+		}
+	}
+	if err := e.pool.batchCall(ctx, elems); err != nil {
+		return nil, err
+	}
+
+	out := make([][]byte, len(results))
+	for i, result := range results {
+		out[i] = result
+	}
+	return out, nil
+}
+
+// toCallArg 将 CallMsg 转换为 eth_call 参数
+func toCallArg(msg ethereum.CallMsg) interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["input"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+	if msg.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(msg.Gas)
+	}
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
+	}
+	return arg
+}
+
+// toBlockNumArg 将区块号转换为 JSON-RPC 区块参数（nil 表示最新区块）
+func toBlockNumArg(number *big.Int) string {
+	if number == nil {
+		return "latest"
+	}
+	if number.Sign() < 0 {
+		return rpc.BlockNumber(number.Int64()).String()
+	}
+	return hexutil.EncodeBig(number)
+}
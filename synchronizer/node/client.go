@@ -2,49 +2,293 @@ package node
 
 import (
 	"context"
-	"github.com/urfave/cli/v2"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/urfave/cli/v2"
+	"go-contracts/config"
 	"go-contracts/contract"
 	"math/big"
+	"strings"
 )
 
 // EthClient 以太坊/BSC客户端接口
+// 同时满足 bind.ContractBackend，可直接用于创建合约绑定实例
 type EthClient interface {
+	// 获取链ID
+	ChainID(ctx context.Context) (*big.Int, error)
+	// 获取最新区块号
+	BlockNumber(ctx context.Context) (uint64, error)
+	// 根据区块号获取区块头（number 为 nil 时返回最新区块头）
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	// 根据区块哈希获取区块头
+	HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error)
+	// 根据区块号获取完整区块（number 为 nil 时返回最新区块）
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	// 根据区块哈希获取完整区块
+	BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error)
+	// 查询区块中的交易数量
+	TransactionCount(ctx context.Context, blockHash common.Hash) (uint, error)
+	// 根据交易哈希查询交易及其是否仍在交易池中
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+	// 查询交易收据
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	// 按条件查询日志
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+	// 订阅日志（需要支持订阅的节点连接，如 websocket）
+	SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error)
+	// 是否有支持订阅的节点（HTTP 节点不支持）
+	SupportsSubscriptions() bool
+
+	// 查询账户余额
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	// 查询账户在指定区块的 nonce
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	// 查询账户在交易池中的 nonce
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	// 建议的 Gas 价格
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	// 建议的小费（EIP-1559）
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	// 估算 Gas 用量
+	EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+
+	// 查询合约代码
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+	// 查询交易池状态下的合约代码
+	PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error)
+	// 执行只读合约调用
+	CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	// 在交易池状态（pending 区块）上执行调用
+	PendingCallContract(ctx context.Context, call ethereum.CallMsg) ([]byte, error)
+	// 在交易池状态（pending 区块）上估算 Gas 用量
+	PendingEstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+	// 广播已签名交易
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+
+	// 批量获取区块头及交易数量（JSON-RPC 批量请求）
+	BatchBlockSummaries(ctx context.Context, numbers []uint64) ([]*BlockSummary, error)
+	// 批量获取交易收据（JSON-RPC 批量请求）
+	BatchTransactionReceipts(ctx context.Context, hashes []common.Hash) ([]*types.Receipt, error)
+	// 批量执行只读合约调用（JSON-RPC 批量请求）
+	BatchCallContract(ctx context.Context, msgs []ethereum.CallMsg, blockNumber *big.Int) ([][]byte, error)
+
 	// 获取ERC20合约实例
 	GetERC20Contract(ctx context.Context, contractAddress common.Address) (*contract.Erc20, error)
 	// 查询授权额度
 	ERC20Allowance(ctx context.Context, contractAddress common.Address, owner, spender common.Address) (*big.Int, error)
 	// 设置授权
-	ERC20Approve(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, spender common.Address, value *big.Int) (*big.Int, error)
+	ERC20Approve(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, spender common.Address, value *big.Int) (*types.Transaction, error)
 	// 转账
-	ERC20Transfer(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, to common.Address, value *big.Int) (*big.Int, error)
+	ERC20Transfer(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, to common.Address, value *big.Int) (*types.Transaction, error)
 	// 授权转账
-	ERC20TransferFrom(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, from, to common.Address, value *big.Int) (*big.Int, error)
+	ERC20TransferFrom(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, from, to common.Address, value *big.Int) (*types.Transaction, error)
 	// 查询余额
 	ERC20Balance(ctx context.Context, contractAddress common.Address, account common.Address) (*big.Int, error)
 	// 查询总供应量
 	ERC20TotalSupply(ctx context.Context, contractAddress common.Address) (*big.Int, error)
 	// 获取代币信息
 	ERC20TokenInfo(ctx context.Context, contractAddress common.Address) (string, string, uint8, error)
+
+	// 各节点的运行统计
+	EndpointStats() []EndpointStats
+	// 关闭连接
+	Close()
 }
 
-// ethClientImpl 实现EthClient接口
+var _ bind.ContractBackend = (EthClient)(nil)
+
+// ethClientImpl 实现EthClient接口，请求经由连接池在多个节点间故障转移
 type ethClientImpl struct {
-	client *ethclient.Client
+	pool *Pool
 }
 
 // NewEthClientImpl 创建新的EthClient实现
-func NewEthClientImpl(client *ethclient.Client) EthClient {
+func NewEthClientImpl(pool *Pool) EthClient {
 	return &ethClientImpl{
-		client: client,
+		pool: pool,
 	}
 }
 
+// call 在连接池上执行带返回值的请求
+func call[T any](ctx context.Context, p *Pool, fn func(client *ethclient.Client) (T, error)) (T, error) {
+	var out T
+	err := p.do(ctx, func(client *ethclient.Client) error {
+		var err error
+		out, err = fn(client)
+		return err
+	})
+	return out, err
+}
+
+// ChainID 获取链ID
+func (e *ethClientImpl) ChainID(ctx context.Context) (*big.Int, error) {
+	return call(ctx, e.pool, func(c *ethclient.Client) (*big.Int, error) { return c.ChainID(ctx) })
+}
+
+// BlockNumber 获取最新区块号
+func (e *ethClientImpl) BlockNumber(ctx context.Context) (uint64, error) {
+	return call(ctx, e.pool, func(c *ethclient.Client) (uint64, error) { return c.BlockNumber(ctx) })
+}
+
+// HeaderByNumber 根据区块号获取区块头
+func (e *ethClientImpl) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return call(ctx, e.pool, func(c *ethclient.Client) (*types.Header, error) { return c.HeaderByNumber(ctx, number) })
+}
+
+// HeaderByHash 根据区块哈希获取区块头
+func (e *ethClientImpl) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	return call(ctx, e.pool, func(c *ethclient.Client) (*types.Header, error) { return c.HeaderByHash(ctx, hash) })
+}
+
+// BlockByNumber 根据区块号获取完整区块
+func (e *ethClientImpl) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return call(ctx, e.pool, func(c *ethclient.Client) (*types.Block, error) { return c.BlockByNumber(ctx, number) })
+}
+
+// BlockByHash 根据区块哈希获取完整区块
+func (e *ethClientImpl) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return call(ctx, e.pool, func(c *ethclient.Client) (*types.Block, error) { return c.BlockByHash(ctx, hash) })
+}
+
+// TransactionCount 查询区块中的交易数量
+func (e *ethClientImpl) TransactionCount(ctx context.Context, blockHash common.Hash) (uint, error) {
+	return call(ctx, e.pool, func(c *ethclient.Client) (uint, error) { return c.TransactionCount(ctx, blockHash) })
+}
+
+// TransactionByHash 根据交易哈希查询交易
+func (e *ethClientImpl) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	var (
+		tx        *types.Transaction
+		isPending bool
+	)
+	err := e.pool.do(ctx, func(c *ethclient.Client) error {
+		var err error
+		tx, isPending, err = c.TransactionByHash(ctx, hash)
+		return err
+	})
+	return tx, isPending, err
+}
+
+// TransactionReceipt 查询交易收据
+func (e *ethClientImpl) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return call(ctx, e.pool, func(c *ethclient.Client) (*types.Receipt, error) { return c.TransactionReceipt(ctx, txHash) })
+}
+
+// FilterLogs 按条件查询日志
+func (e *ethClientImpl) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return call(ctx, e.pool, func(c *ethclient.Client) ([]types.Log, error) { return c.FilterLogs(ctx, q) })
+}
+
+// SubscribeFilterLogs 订阅日志（只使用支持订阅的节点）
+func (e *ethClientImpl) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return e.pool.subscribe(ctx, func(c *ethclient.Client) (ethereum.Subscription, error) { return c.SubscribeFilterLogs(ctx, q, ch) })
+}
+
+// SupportsSubscriptions 是否有支持订阅的节点
+func (e *ethClientImpl) SupportsSubscriptions() bool {
+	return e.pool.supportsSubscriptions()
+}
+
+// BalanceAt 查询账户余额
+func (e *ethClientImpl) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return call(ctx, e.pool, func(c *ethclient.Client) (*big.Int, error) { return c.BalanceAt(ctx, account, blockNumber) })
+}
+
+// NonceAt 查询账户在指定区块的 nonce
+func (e *ethClientImpl) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return call(ctx, e.pool, func(c *ethclient.Client) (uint64, error) { return c.NonceAt(ctx, account, blockNumber) })
+}
+
+// PendingNonceAt 查询账户在交易池中的 nonce
+func (e *ethClientImpl) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return call(ctx, e.pool, func(c *ethclient.Client) (uint64, error) { return c.PendingNonceAt(ctx, account) })
+}
+
+// SuggestGasPrice 建议的 Gas 价格
+func (e *ethClientImpl) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return call(ctx, e.pool, func(c *ethclient.Client) (*big.Int, error) { return c.SuggestGasPrice(ctx) })
+}
+
+// SuggestGasTipCap 建议的小费
+func (e *ethClientImpl) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return call(ctx, e.pool, func(c *ethclient.Client) (*big.Int, error) { return c.SuggestGasTipCap(ctx) })
+}
+
+// EstimateGas 估算 Gas 用量
+func (e *ethClientImpl) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return call(ctx, e.pool, func(c *ethclient.Client) (uint64, error) { return c.EstimateGas(ctx, msg) })
+}
+
+// CodeAt 查询合约代码
+func (e *ethClientImpl) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return call(ctx, e.pool, func(c *ethclient.Client) ([]byte, error) { return c.CodeAt(ctx, account, blockNumber) })
+}
+
+// PendingCodeAt 查询交易池状态下的合约代码
+func (e *ethClientImpl) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return call(ctx, e.pool, func(c *ethclient.Client) ([]byte, error) { return c.PendingCodeAt(ctx, account) })
+}
+
+// CallContract 执行只读合约调用
+func (e *ethClientImpl) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return call(ctx, e.pool, func(c *ethclient.Client) ([]byte, error) { return c.CallContract(ctx, msg, blockNumber) })
+}
+
+// PendingCallContract 在交易池状态上执行调用
+func (e *ethClientImpl) PendingCallContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error) {
+	return call(ctx, e.pool, func(c *ethclient.Client) ([]byte, error) { return c.PendingCallContract(ctx, msg) })
+}
+
+// PendingEstimateGas 在交易池状态上估算 Gas 用量
+func (e *ethClientImpl) PendingEstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return call(ctx, e.pool, func(c *ethclient.Client) (uint64, error) {
+		return c.EstimateGasAtBlock(ctx, msg, big.NewInt(int64(rpc.PendingBlockNumber)))
+	})
+}
+
+// SendTransaction 广播已签名交易
+// 广播超时等临时错误时节点可能已经接收了交易，重试返回 already known、或重试出错但交易已能查到时视为发送成功，
+// 避免调用方把已进入交易池的交易当作发送失败而重新使用其 nonce
+func (e *ethClientImpl) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	retried := false
+	return e.pool.do(ctx, func(c *ethclient.Client) error {
+		err := c.SendTransaction(ctx, tx)
+		if err == nil || isAlreadyKnown(err) {
+			return nil
+		}
+		if retried && !isTransientError(err) {
+			if _, _, lookupErr := c.TransactionByHash(ctx, tx.Hash()); lookupErr == nil {
+				return nil
+			}
+		}
+		retried = true
+		return err
+	})
+}
+
+// isAlreadyKnown 节点返回的错误是否表示同一交易已在交易池中
+func isAlreadyKnown(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already known") || strings.Contains(msg, "known transaction")
+}
+
+// EndpointStats 返回各节点的运行统计
+func (e *ethClientImpl) EndpointStats() []EndpointStats {
+	return e.pool.Stats()
+}
+
+// Close 关闭连接
+func (e *ethClientImpl) Close() {
+	e.pool.Close()
+}
+
 // GetERC20Contract 获取ERC20合约实例
 func (e *ethClientImpl) GetERC20Contract(ctx context.Context, contractAddress common.Address) (*contract.Erc20, error) {
-	return contract.NewErc20(contractAddress, e.client)
+	return contract.NewErc20(contractAddress, e)
 }
 
 // ERC20Allowance 查询授权额度
@@ -58,51 +302,33 @@ func (e *ethClientImpl) ERC20Allowance(ctx context.Context, contractAddress comm
 }
 
 // ERC20Approve 设置授权
-func (e *ethClientImpl) ERC20Approve(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, spender common.Address, value *big.Int) (*big.Int, error) {
+func (e *ethClientImpl) ERC20Approve(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, spender common.Address, value *big.Int) (*types.Transaction, error) {
 	contract, err := e.GetERC20Contract(ctx, contractAddress)
 	if err != nil {
 		return nil, err
 	}
 
-	tx, err := contract.Approve(auth, spender, value)
-	if err != nil {
-		return nil, err
-	}
-
-	// 返回交易哈希的字节表示作为ID
-	return new(big.Int).SetBytes(tx.Hash().Bytes()), nil
+	return contract.Approve(auth, spender, value)
 }
 
 // ERC20Transfer 转账
-func (e *ethClientImpl) ERC20Transfer(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, to common.Address, value *big.Int) (*big.Int, error) {
+func (e *ethClientImpl) ERC20Transfer(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, to common.Address, value *big.Int) (*types.Transaction, error) {
 	contract, err := e.GetERC20Contract(ctx, contractAddress)
 	if err != nil {
 		return nil, err
 	}
 
-	tx, err := contract.Transfer(auth, to, value)
-	if err != nil {
-		return nil, err
-	}
-
-	// 返回交易哈希的字节表示作为ID
-	return new(big.Int).SetBytes(tx.Hash().Bytes()), nil
+	return contract.Transfer(auth, to, value)
 }
 
 // ERC20TransferFrom 授权转账
-func (e *ethClientImpl) ERC20TransferFrom(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, from, to common.Address, value *big.Int) (*big.Int, error) {
+func (e *ethClientImpl) ERC20TransferFrom(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, from, to common.Address, value *big.Int) (*types.Transaction, error) {
 	contract, err := e.GetERC20Contract(ctx, contractAddress)
 	if err != nil {
 		return nil, err
 	}
 
-	tx, err := contract.TransferFrom(auth, from, to, value)
-	if err != nil {
-		return nil, err
-	}
-
-	// 返回交易哈希的字节表示作为ID
-	return new(big.Int).SetBytes(tx.Hash().Bytes()), nil
+	return contract.TransferFrom(auth, from, to, value)
 }
 
 // ERC20Balance 查询余额
@@ -150,12 +376,16 @@ func (e *ethClientImpl) ERC20TokenInfo(ctx context.Context, contractAddress comm
 	return name, symbol, decimals, nil
 }
 
-// DialEthClient 连接以太坊/BSC节点
+// DialEthClient 连接单个以太坊/BSC节点
 func DialEthClient(ctx cli.Context, rpcUrl string) (EthClient, error) {
-	client, err := ethclient.Dial(rpcUrl)
+	return DialEthClientPool(ctx.Context, &config.RPCConfig{Endpoints: []string{rpcUrl}})
+}
+
+// DialEthClientPool 按配置连接多个节点，返回带健康检查和故障转移的客户端
+func DialEthClientPool(ctx context.Context, cfg *config.RPCConfig) (EthClient, error) {
+	pool, err := NewPool(ctx, cfg)
 	if err != nil {
 		return nil, err
 	}
-
-	return NewEthClientImpl(client), nil
+	return NewEthClientImpl(pool), nil
 }
@@ -0,0 +1,400 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go-contracts/config"
+	"go-contracts/util"
+)
+
+const (
+	defaultHealthInterval = 15 * time.Second
+	defaultMaxHeadLag     = 5
+	defaultMaxRetries     = 3
+	defaultRetryBackoff   = 200 * time.Millisecond
+	probeTimeout          = 5 * time.Second
+)
+
+// EndpointStats 单个节点的运行统计
+type EndpointStats struct {
+	URL       string    `json:"url"`        // 节点地址（已隐去路径和参数）
+	Healthy   bool      `json:"healthy"`    // 是否健康
+	Head      uint64    `json:"head"`       // 最近一次探测到的链头
+	HeadLag   uint64    `json:"head_lag"`   // 落后最高节点的区块数
+	Requests  uint64    `json:"requests"`   // 请求总数
+	Failures  uint64    `json:"failures"`   // 失败次数
+	LastError string    `json:"last_error"` // 最近一次错误
+	LastCheck time.Time `json:"last_check"` // 最近一次健康检查时间
+}
+
+// endpoint 连接池中的单个节点
+type endpoint struct {
+	url       string
+	client    *ethclient.Client
+	healthy   atomic.Bool
+	head      atomic.Uint64
+	requests  atomic.Uint64
+	failures  atomic.Uint64
+	mu        sync.Mutex // 保护 client、lastError、lastCheck
+	lastError string
+	lastCheck time.Time
+}
+
+// Pool 多节点连接池
+// 请求优先发往健康且优先级最高的节点，临时错误时退避重试并切换到下一个节点
+type Pool struct {
+	endpoints    []*endpoint
+	maxHeadLag   uint64
+	maxRetries   int
+	retryBackoff time.Duration
+	batchSize    int
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+// NewPool 根据配置创建连接池并启动健康检查
+func NewPool(ctx context.Context, cfg *config.RPCConfig) (*Pool, error) {
+	urls := cfg.Endpoints
+	if len(urls) == 0 {
+		urls = []string{config.RAW_URL}
+	}
+
+	p := &Pool{
+		maxHeadLag:   cfg.MaxHeadLag,
+		maxRetries:   cfg.MaxRetries,
+		retryBackoff: time.Duration(cfg.RetryBackoffMs) * time.Millisecond,
+		batchSize:    cfg.BatchSize,
+	}
+	if p.maxHeadLag == 0 {
+		p.maxHeadLag = defaultMaxHeadLag
+	}
+	if p.maxRetries <= 0 {
+		p.maxRetries = defaultMaxRetries
+	}
+	if p.retryBackoff <= 0 {
+		p.retryBackoff = defaultRetryBackoff
+	}
+	if p.batchSize <= 0 {
+		p.batchSize = defaultBatchSize
+	}
+
+	dialed := 0
+	for _, u := range urls {
+		ep := &endpoint{url: u}
+		if err := ep.dial(ctx); err != nil {
+			util.Log.Warn("连接节点失败，等待健康检查重试", "url", redactURL(u), "err", err)
+		} else {
+			ep.healthy.Store(true)
+			dialed++
+		}
+		p.endpoints = append(p.endpoints, ep)
+	}
+	if dialed == 0 {
+		return nil, fmt.Errorf("所有节点均连接失败（共 %d 个）", len(urls))
+	}
+
+	interval := time.Duration(cfg.HealthInterval) * time.Second
+	if interval <= 0 {
+		interval = defaultHealthInterval
+	}
+	probeCtx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.probe(probeCtx)
+	p.wg.Add(1)
+	go p.healthLoop(probeCtx, interval)
+
+	return p, nil
+}
+
+// dial 建立节点连接
+func (ep *endpoint) dial(ctx context.Context) error {
+	client, err := ethclient.DialContext(ctx, ep.url)
+	if err != nil {
+		ep.recordError(err)
+		return err
+	}
+	ep.mu.Lock()
+	ep.client = client
+	ep.mu.Unlock()
+	return nil
+}
+
+// getClient 获取节点连接（未连接时为 nil）
+func (ep *endpoint) getClient() *ethclient.Client {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	return ep.client
+}
+
+// recordError 记录最近一次错误
+func (ep *endpoint) recordError(err error) {
+	ep.mu.Lock()
+	ep.lastError = err.Error()
+	ep.mu.Unlock()
+}
+
+// healthLoop 定期探测各节点链头
+func (p *Pool) healthLoop(ctx context.Context, interval time.Duration) {
+	defer p.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.probe(ctx)
+		}
+	}
+}
+
+// probe 查询所有节点的链头，落后最高链头超过 maxHeadLag 或请求失败的节点标记为不健康
+func (p *Pool) probe(ctx context.Context) {
+	ok := make([]bool, len(p.endpoints))
+	var wg sync.WaitGroup
+	for i, ep := range p.endpoints {
+		wg.Add(1)
+		go func(i int, ep *endpoint) {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+			defer cancel()
+
+			ep.mu.Lock()
+			ep.lastCheck = time.Now()
+			ep.mu.Unlock()
+
+			if ep.getClient() == nil {
+				if err := ep.dial(probeCtx); err != nil {
+					return
+				}
+			}
+			head, err := ep.getClient().BlockNumber(probeCtx)
+			if err != nil {
+				ep.recordError(err)
+				return
+			}
+			ep.head.Store(head)
+			ok[i] = true
+		}(i, ep)
+	}
+	wg.Wait()
+
+	maxHead := p.maxHead()
+	for i, ep := range p.endpoints {
+		healthy := ok[i] && maxHead-ep.head.Load() <= p.maxHeadLag
+		if ep.healthy.Swap(healthy) != healthy {
+			util.Log.Info("节点健康状态变化", "url", redactURL(ep.url), "healthy", healthy, "head", ep.head.Load(), "max_head", maxHead)
+		}
+	}
+}
+
+// maxHead 返回所有节点中最高的链头
+func (p *Pool) maxHead() uint64 {
+	var max uint64
+	for _, ep := range p.endpoints {
+		if head := ep.head.Load(); head > max {
+			max = head
+		}
+	}
+	return max
+}
+
+// candidates 返回本次请求的候选节点：健康节点按优先级在前，不健康节点作为兜底
+func (p *Pool) candidates() []*endpoint {
+	out := make([]*endpoint, 0, len(p.endpoints))
+	for _, ep := range p.endpoints {
+		if ep.healthy.Load() && ep.getClient() != nil {
+			out = append(out, ep)
+		}
+	}
+	for _, ep := range p.endpoints {
+		if !ep.healthy.Load() && ep.getClient() != nil {
+			out = append(out, ep)
+		}
+	}
+	return out
+}
+
+// next 选择下一个尝试的节点：优先选择本次请求尚未尝试过的候选节点，全部尝试过后轮流重试
+func (p *Pool) next(tried map[*endpoint]bool, attempt int) *endpoint {
+	candidates := p.candidates()
+	if len(candidates) == 0 {
+		return nil
+	}
+	for _, ep := range candidates {
+		if !tried[ep] {
+			return ep
+		}
+	}
+	return candidates[attempt%len(candidates)]
+}
+
+// do 在连接池上执行请求
+// 临时错误时将当前节点标记为不健康，退避后切换到下一个候选节点重试
+func (p *Pool) do(ctx context.Context, fn func(client *ethclient.Client) error) error {
+	var lastErr error
+	backoff := p.retryBackoff
+	tried := make(map[*endpoint]bool)
+	for attempt := 0; attempt <= p.maxRetries; attempt++ {
+		ep := p.next(tried, attempt)
+		if ep == nil {
+			return errors.New("没有可用的区块链节点")
+		}
+		tried[ep] = true
+
+		ep.requests.Add(1)
+		err := fn(ep.getClient())
+		if err == nil {
+			return nil
+		}
+		ep.failures.Add(1)
+		ep.recordError(err)
+		lastErr = err
+
+		if !isTransientError(err) || ctx.Err() != nil {
+			return err
+		}
+		if ep.healthy.Swap(false) {
+			util.Log.Warn("节点请求失败，切换到其他节点", "url", redactURL(ep.url), "err", err)
+		}
+		if attempt == p.maxRetries {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return fmt.Errorf("重试 %d 次后仍失败: %w", p.maxRetries, lastErr)
+}
+
+// supportsSubscriptions 是否有已连接且支持订阅的节点
+func (p *Pool) supportsSubscriptions() bool {
+	for _, ep := range p.endpoints {
+		if client := ep.getClient(); client != nil && client.Client().SupportsSubscriptions() {
+			return true
+		}
+	}
+	return false
+}
+
+// subscribe 在支持订阅的节点上建立订阅，健康节点优先，失败时依次尝试下一个
+// 没有支持订阅的节点时返回 rpc.ErrNotificationsUnsupported
+func (p *Pool) subscribe(ctx context.Context, fn func(client *ethclient.Client) (ethereum.Subscription, error)) (ethereum.Subscription, error) {
+	var lastErr error = rpc.ErrNotificationsUnsupported
+	for _, ep := range p.candidates() {
+		client := ep.getClient()
+		if !client.Client().SupportsSubscriptions() {
+			continue
+		}
+		ep.requests.Add(1)
+		sub, err := fn(client)
+		if err == nil {
+			return sub, nil
+		}
+		ep.failures.Add(1)
+		ep.recordError(err)
+		lastErr = err
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		util.Log.Warn("节点订阅失败，尝试其他节点", "url", redactURL(ep.url), "err", err)
+	}
+	return nil, lastErr
+}
+
+// Stats 返回各节点的运行统计
+func (p *Pool) Stats() []EndpointStats {
+	maxHead := p.maxHead()
+	stats := make([]EndpointStats, 0, len(p.endpoints))
+	for _, ep := range p.endpoints {
+		ep.mu.Lock()
+		lastError, lastCheck := ep.lastError, ep.lastCheck
+		ep.mu.Unlock()
+		stats = append(stats, EndpointStats{
+			URL:       redactURL(ep.url),
+			Healthy:   ep.healthy.Load(),
+			Head:      ep.head.Load(),
+			HeadLag:   maxHead - ep.head.Load(),
+			Requests:  ep.requests.Load(),
+			Failures:  ep.failures.Load(),
+			LastError: lastError,
+			LastCheck: lastCheck,
+		})
+	}
+	return stats
+}
+
+// Close 停止健康检查并关闭所有节点连接
+func (p *Pool) Close() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+	for _, ep := range p.endpoints {
+		if client := ep.getClient(); client != nil {
+			client.Close()
+		}
+	}
+}
+
+// isTransientError 判断错误是否为可重试的临时错误（网络错误、限流、节点内部错误等）
+func isTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == 429 || httpErr.StatusCode >= 500
+	}
+
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		switch rpcErr.ErrorCode() {
+		case -32005, -32603: // 请求超限、节点内部错误
+			return true
+		}
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{"rate limit", "too many requests", "connection reset", "connection refused", "timeout"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactURL 隐去节点地址中的路径和参数（可能包含 API Key）
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	return u.Scheme + "://" + u.Host
+}
@@ -0,0 +1,217 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go-contracts/config"
+)
+
+// rpcStandIn 本地 JSON-RPC 节点替身
+type rpcStandIn struct {
+	server    *httptest.Server
+	head      atomic.Uint64
+	chainID   uint64
+	status    atomic.Int32 // 非 0 时直接返回该 HTTP 状态码
+	rpcError  atomic.Bool  // 为 true 时返回 JSON-RPC 执行错误
+	httpCalls atomic.Int32 // 收到的 HTTP 请求数
+}
+
+type rpcRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// handle 处理单个 JSON-RPC 调用
+func (s *rpcStandIn) handle(req rpcRequest) map[string]interface{} {
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	switch {
+	case s.rpcError.Load():
+		resp["error"] = map[string]interface{}{"code": -32000, "message": "execution reverted"}
+	case req.Method == "eth_blockNumber":
+		resp["result"] = fmt.Sprintf("0x%x", s.head.Load())
+	case req.Method == "eth_chainId":
+		resp["result"] = fmt.Sprintf("0x%x", s.chainID)
+	case req.Method == "eth_sendRawTransaction":
+		resp["error"] = map[string]interface{}{"code": -32000, "message": "already known"}
+	case req.Method == "eth_getBlockByNumber":
+		var number hexutil.Uint64
+		json.Unmarshal(req.Params[0], &number)
+		resp["result"] = standInBlock(uint64(number))
+	default:
+		resp["error"] = map[string]interface{}{"code": -32601, "message": "method not found"}
+	}
+	return resp
+}
+
+// standInBlock 构造区块号为 number、交易数量为 number%3 的区块 JSON
+func standInBlock(number uint64) map[string]interface{} {
+	header := &types.Header{Number: new(big.Int).SetUint64(number), Difficulty: big.NewInt(0)}
+	raw, _ := json.Marshal(header)
+	var block map[string]interface{}
+	json.Unmarshal(raw, &block)
+	txs := make([]common.Hash, number%3)
+	for i := range txs {
+		txs[i] = common.BigToHash(big.NewInt(int64(i + 1)))
+	}
+	block["transactions"] = txs
+	return block
+}
+
+func newRPCStandIn(t *testing.T, head, chainID uint64) *rpcStandIn {
+	s := &rpcStandIn{chainID: chainID}
+	s.head.Store(head)
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.httpCalls.Add(1)
+		if code := s.status.Load(); code != 0 {
+			w.WriteHeader(int(code))
+			return
+		}
+		var body json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		// 批量请求为 JSON 数组
+		if len(body) > 0 && body[0] == '[' {
+			var reqs []rpcRequest
+			json.Unmarshal(body, &reqs)
+			resps := make([]map[string]interface{}, len(reqs))
+			for i, req := range reqs {
+				resps[i] = s.handle(req)
+			}
+			json.NewEncoder(w).Encode(resps)
+			return
+		}
+		var req rpcRequest
+		json.Unmarshal(body, &req)
+		json.NewEncoder(w).Encode(s.handle(req))
+	}))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func newTestPool(t *testing.T, standIns ...*rpcStandIn) *Pool {
+	cfg := &config.RPCConfig{MaxHeadLag: 5, MaxRetries: 2, RetryBackoffMs: 1, HealthInterval: 3600, BatchSize: 2}
+	for _, s := range standIns {
+		cfg.Endpoints = append(cfg.Endpoints, s.server.URL)
+	}
+	pool, err := NewPool(context.Background(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+// TestPool_Failover 测试主节点返回临时错误时切换到备用节点
+func TestPool_Failover(t *testing.T) {
+	primary := newRPCStandIn(t, 100, 97)
+	backup := newRPCStandIn(t, 100, 98)
+	pool := newTestPool(t, primary, backup)
+	client := NewEthClientImpl(pool)
+
+	chainID, err := client.ChainID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(97), chainID.Uint64(), "健康时应优先使用主节点")
+
+	primary.status.Store(http.StatusTooManyRequests)
+	chainID, err = client.ChainID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(98), chainID.Uint64(), "主节点限流时应切换到备用节点")
+
+	stats := pool.Stats()
+	assert.False(t, stats[0].Healthy)
+	assert.Equal(t, uint64(1), stats[0].Failures)
+	assert.True(t, stats[1].Healthy)
+
+	// 主节点恢复后，下一次健康检查应重新标记为健康
+	primary.status.Store(0)
+	pool.probe(context.Background())
+	chainID, err = client.ChainID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(97), chainID.Uint64())
+}
+
+// TestPool_HeadLag 测试落后过多的节点被标记为不健康
+func TestPool_HeadLag(t *testing.T) {
+	lagging := newRPCStandIn(t, 100, 97)
+	synced := newRPCStandIn(t, 200, 98)
+	pool := newTestPool(t, lagging, synced)
+
+	stats := pool.Stats()
+	assert.False(t, stats[0].Healthy)
+	assert.Equal(t, uint64(100), stats[0].HeadLag)
+	assert.True(t, stats[1].Healthy)
+
+	chainID, err := NewEthClientImpl(pool).ChainID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(98), chainID.Uint64(), "应优先使用同步到最新的节点")
+}
+
+// TestPool_NoRetryOnExecutionError 测试非临时错误不重试
+func TestPool_NoRetryOnExecutionError(t *testing.T) {
+	primary := newRPCStandIn(t, 100, 97)
+	backup := newRPCStandIn(t, 100, 98)
+	pool := newTestPool(t, primary, backup)
+
+	primary.rpcError.Store(true)
+	_, err := NewEthClientImpl(pool).ChainID(context.Background())
+	assert.Error(t, err)
+
+	stats := pool.Stats()
+	assert.True(t, stats[0].Healthy, "执行错误不应影响节点健康状态")
+	assert.Equal(t, uint64(0), stats[1].Requests, "执行错误不应切换节点")
+	assert.Equal(t, uint64(1), stats[0].Failures)
+}
+
+// TestSendTransaction_AlreadyKnown 测试广播失败后重试返回 already known 时视为发送成功
+func TestSendTransaction_AlreadyKnown(t *testing.T) {
+	primary := newRPCStandIn(t, 100, 97)
+	backup := newRPCStandIn(t, 100, 98)
+	pool := newTestPool(t, primary, backup)
+
+	primary.status.Store(http.StatusBadGateway)
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000})
+	require.NoError(t, NewEthClientImpl(pool).SendTransaction(context.Background(), tx))
+	assert.Equal(t, uint64(1), pool.Stats()[1].Requests)
+}
+
+// TestBatchBlockSummaries 测试批量请求按 batch_size 分组且结果保持顺序
+func TestPool_SubscribeUnsupportedOverHTTP(t *testing.T) {
+	pool := newTestPool(t, newRPCStandIn(t, 100, 97))
+	client := NewEthClientImpl(pool)
+
+	assert.False(t, client.SupportsSubscriptions())
+	_, err := client.SubscribeFilterLogs(context.Background(), ethereum.FilterQuery{}, make(chan types.Log))
+	assert.ErrorIs(t, err, rpc.ErrNotificationsUnsupported)
+}
+
+func TestBatchBlockSummaries(t *testing.T) {
+	standIn := newRPCStandIn(t, 100, 97)
+	pool := newTestPool(t, standIn)
+	before := standIn.httpCalls.Load()
+
+	summaries, err := NewEthClientImpl(pool).BatchBlockSummaries(context.Background(), []uint64{10, 11, 12, 13, 14})
+	require.NoError(t, err)
+	require.Len(t, summaries, 5)
+	for i, summary := range summaries {
+		number := uint64(10 + i)
+		assert.Equal(t, number, summary.Header.Number.Uint64())
+		assert.Equal(t, int(number%3), summary.TxCount)
+	}
+	assert.Equal(t, int32(3), standIn.httpCalls.Load()-before, "5 个调用按每批 2 个应发送 3 个批量请求")
+}
@@ -0,0 +1,92 @@
+package synchronizer
+
+import (
+	"context"
+	"fmt"
+	"go-contracts/util"
+	"math/big"
+)
+
+// maxReorgDepth 回溯查找共同祖先的最大深度，超过后视为不可恢复的错误
+const maxReorgDepth = 128
+
+// Reorg 描述一次链重组：分叉点之上的区块均已成为孤块
+type Reorg struct {
+	ForkPoint uint64 // 共同祖先区块号（该区块及以下仍在主链上）
+	Detected  uint64 // 检测到父哈希不一致的区块号
+}
+
+// blockHash 查询已同步区块的哈希，优先使用内存记录，其次查询数据库
+func (s *Synchronizer) blockHash(number uint64) (string, bool, error) {
+	if hash, ok := s.recentHashes[number]; ok {
+		return hash, true, nil
+	}
+	return s.db.BlockHashByNumber(number)
+}
+
+// rememberHash 记录已发送区块的哈希，并清理超出回溯深度的旧记录
+func (s *Synchronizer) rememberHash(number uint64, hash string) {
+	s.recentHashes[number] = hash
+	if number >= maxReorgDepth {
+		delete(s.recentHashes, number-maxReorgDepth)
+	}
+}
+
+// findForkPoint 从 number 开始向下回溯，找到本地记录与链上一致的共同祖先
+// number 必须是已同步的区块，只回溯本地有记录的区块
+func (s *Synchronizer) findForkPoint(ctx context.Context, number uint64) (uint64, error) {
+	for depth := uint64(0); depth < maxReorgDepth; depth++ {
+		if number < depth {
+			break
+		}
+		n := number - depth
+
+		stored, ok, err := s.blockHash(n)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			if depth == 0 {
+				return 0, fmt.Errorf("区块 %d 没有本地记录，无法回溯链重组", n)
+			}
+			// 本地没有更早的记录，上方已同步的区块均为孤块，从该区块之后重新同步
+			return n, nil
+		}
+
+		header, err := s.ethClient.HeaderByNumber(ctx, new(big.Int).SetUint64(n))
+		if err != nil {
+			return 0, fmt.Errorf("获取区块头失败（区块号: %d）: %w", n, err)
+		}
+		if header.Hash().Hex() == stored {
+			return n, nil
+		}
+		util.Log.Warn("发现孤块", "number", n, "stored", stored, "canonical", header.Hash().Hex())
+	}
+	return 0, fmt.Errorf("链重组深度超过 %d 个区块（检测位置: %d）", maxReorgDepth, number)
+}
+
+// handleReorg 回溯到共同祖先，通知处理器回滚并从分叉点之后重新同步
+func (s *Synchronizer) handleReorg(ctx context.Context, detected uint64) error {
+	if detected == 0 {
+		return fmt.Errorf("创世区块父哈希不一致")
+	}
+	forkPoint, err := s.findForkPoint(ctx, detected-1)
+	if err != nil {
+		return err
+	}
+	util.Log.Warn("检测到链重组", "detected", detected, "fork_point", forkPoint)
+
+	select {
+	case s.blockChannel <- &BlockBatch{Reorg: &Reorg{ForkPoint: forkPoint, Detected: detected}}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	for number := range s.recentHashes {
+		if number > forkPoint {
+			delete(s.recentHashes, number)
+		}
+	}
+	s.lastBlockNum = forkPoint + 1
+	return nil
+}
@@ -2,54 +2,118 @@ package synchronizer
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"go-contracts/config"
+	"go-contracts/database"
 	"go-contracts/models"
 	"go-contracts/synchronizer/node"
 	"go-contracts/util"
-	"os"
+	"math/big"
 	"sync/atomic"
 	"time"
 )
 
+const (
+	defaultRangeSize = 20 // 每个同步范围默认的区块数
+	defaultWorkers   = 4  // 追赶阶段默认的并发数
+	maxFetchAttempts = 3  // 日志与区块不一致时重新拉取范围的最大次数
+)
+
 // BlockBatch 表示一批区块数据
+// Reorg 非空时表示发生链重组，处理器需先回滚分叉点之上的数据
 type BlockBatch struct {
-	Blocks []*models.Block
+	Blocks            []*models.Block
+	ERC20Transactions []*models.ERC20Transaction // 范围内配置合约的 Transfer、Approval 记录
+	Reorg             *Reorg
 }
 
 // Synchronizer 从区块链同步事件的组件
 type Synchronizer struct {
-	interval     time.Duration           // 同步间隔
-	shutdown     context.CancelCauseFunc // 取消函数（用于主动退出）
-	stopped      atomic.Bool             // 停止状态标记
-	ethClient    node.EthClient          // 以太坊客户端
-	blockChannel chan<- *BlockBatch      // 区块数据通道
-	lastBlockNum uint64                  // 最后处理的区块号
+	interval       time.Duration           // 同步间隔
+	shutdown       context.CancelCauseFunc // 取消函数（用于主动退出）
+	stopped        atomic.Bool             // 停止状态标记
+	ethClient      node.EthClient          // 以太坊客户端
+	db             *database.DB            // 数据库连接（读取同步检查点）
+	blockChannel   chan<- *BlockBatch      // 区块数据通道
+	confirmations  uint64                  // 确认数（只同步低于链头 N 个区块的数据）
+	rangeSize      uint64                  // 每个同步范围的区块数
+	workers        int                     // 追赶阶段并发拉取的范围数
+	erc20Contracts []common.Address        // 需要索引 Transfer、Approval 日志的 ERC20 合约
+	lastBlockNum   uint64                  // 最后处理的区块号
+	recentHashes   map[uint64]string       // 最近发送区块的哈希（用于检测链重组）
 }
 
 // NewSynchronizer 创建同步器实例
-func NewSynchronizer(cfg *config.IndexerConfig, ethClient node.EthClient, blockChannel chan<- *BlockBatch, shutdown context.CancelCauseFunc) (*Synchronizer, error) {
+func NewSynchronizer(cfg *config.IndexerConfig, db *database.DB, ethClient node.EthClient, blockChannel chan<- *BlockBatch, shutdown context.CancelCauseFunc) (*Synchronizer, error) {
 	// 从配置读取同步间隔（默认 10 秒）
 	interval := time.Duration(cfg.Interval) * time.Second
 	if interval <= 0 {
 		interval = 10 * time.Second
 	}
 
-	// 初始化最后处理的区块号
-	// 在实际环境中，应该从数据库读取最后处理的区块号
-	// 这里设置为100，跳过已经存在的区块记录
-	lastBlockNum := uint64(100)
-	
+	// 从检查点恢复下一个待同步的区块号
+	lastBlockNum, err := loadStartBlock(cfg, db)
+	if err != nil {
+		return nil, err
+	}
+	util.Log.Info("同步起始区块", "block", lastBlockNum, "confirmations", cfg.Confirmations)
+
+	rangeSize := cfg.RangeSize
+	if rangeSize == 0 {
+		rangeSize = defaultRangeSize
+	}
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+
+	erc20Contracts := make([]common.Address, 0, len(cfg.ERC20Contracts))
+	for _, addr := range cfg.ERC20Contracts {
+		if !common.IsHexAddress(addr) {
+			return nil, fmt.Errorf("无效的ERC20合约地址: %s", addr)
+		}
+		erc20Contracts = append(erc20Contracts, common.HexToAddress(addr))
+	}
+
 	return &Synchronizer{
-		interval:     interval,
-		shutdown:     shutdown,
-		ethClient:    ethClient,
-		blockChannel: blockChannel,
-		lastBlockNum: lastBlockNum,
+		interval:       interval,
+		shutdown:       shutdown,
+		ethClient:      ethClient,
+		db:             db,
+		blockChannel:   blockChannel,
+		confirmations:  cfg.Confirmations,
+		rangeSize:      rangeSize,
+		workers:        workers,
+		erc20Contracts: erc20Contracts,
+		lastBlockNum:   lastBlockNum,
+		recentHashes:   make(map[uint64]string),
 	}, nil
 }
 
+// loadStartBlock 计算下一个待同步的区块号
+// 优先使用已提交的检查点，其次使用 blocks 表中的最大区块号，最后使用配置的起始区块
+func loadStartBlock(cfg *config.IndexerConfig, db *database.DB) (uint64, error) {
+	checkpoint, err := db.GetCheckpoint(models.CheckpointIndexer)
+	if err != nil {
+		return 0, err
+	}
+	if checkpoint != nil {
+		return checkpoint.BlockNumber + 1, nil
+	}
+
+	maxBlock, ok, err := db.MaxBlockNumber()
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		return maxBlock + 1, nil
+	}
+
+	return cfg.StartBlock, nil
+}
+
 // Start 启动同步器（非阻塞）
 func (s *Synchronizer) Start(ctx context.Context) error {
 	if s.stopped.Load() {
@@ -81,69 +145,180 @@ func (s *Synchronizer) runLoop(ctx context.Context) {
 	}
 }
 
-// syncOnce 单次同步逻辑（实际从区块链拉取事件）
+// syncOnce 单次同步逻辑（从区块链拉取区块头并发送到处理通道）
 func (s *Synchronizer) syncOnce(ctx context.Context) error {
 	util.Log.Debug("执行区块链事件同步...")
-	
-	// 模拟获取最新区块号
-	// 实际实现应该通过 ethClient 获取最新区块号
-	// 为了演示，这里简单模拟
+
+	// 1. 获取链上最新区块号，并扣除确认数得到可同步的最高区块
+	latest, err := s.ethClient.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("获取最新区块号失败: %w", err)
+	}
+	if latest < s.confirmations {
+		return nil
+	}
+	head := latest - s.confirmations
+	if s.lastBlockNum > head {
+		util.Log.Debug("已同步到最新区块，等待新区块", "next", s.lastBlockNum, "head", head)
+		return nil
+	}
+
+	// 2. 落后超过一个范围时进入追赶阶段，并发拉取多个范围
 	startBlock := s.lastBlockNum
-	endBlock := startBlock + 19 // 每次扫描20个区块（包括startBlock）
-	
-	util.Log.Info("开始扫描区块范围", "start", startBlock, "end", endBlock)
-	
-	// 创建区块批次
-	blockBatch := &BlockBatch{
-		Blocks: make([]*models.Block, 0, 20),
-	}
-	
-	// 模拟扫描20个区块
-	// 实际实现应该使用 ethClient 获取每个区块的数据
-	for i := startBlock; i <= endBlock; i++ {
-		// 模拟区块数据
-		// 在实际实现中，这里应该调用 ethclient 获取区块数据，然后转换为 models.Block
-		// 生成固定长度的模拟区块哈希，确保不会超过数据库字段长度限制
-		// 使用i的哈希值加上随机字符串，然后截取固定长度
-		blockNumHex := fmt.Sprintf("%x", i)
-		parentNumHex := fmt.Sprintf("%x", i-1)
-		blockHash := fmt.Sprintf("0x%064s", blockNumHex)[:66]  // 确保长度为66
-		parentHash := fmt.Sprintf("0x%064s", parentNumHex)[:66]  // 确保长度为66
-		
-		// 从环境变量读取矿工地址，使用默认值作为备选
-		minerAddress := os.Getenv("MINER_ADDRESS")
-		if minerAddress == "" {
-			return errors.New("MINER_ADDRESS 环境变量未设置")
+	if head-startBlock+1 > s.rangeSize {
+		return s.catchUp(ctx, head)
+	}
+
+	// 3. 接近链头时逐个拉取 [startBlock, head]
+	util.Log.Info("开始扫描区块范围", "start", startBlock, "end", head)
+	blockBatch, err := s.fetchRange(ctx, startBlock, head, false)
+	if err != nil {
+		return err
+	}
+	_, err = s.emitBatch(ctx, blockBatch)
+	return err
+}
+
+// fetchRange 拉取 [start, end] 范围内的区块及 ERC20 交易记录
+// batched 为 true 时以批量请求拉取区块头；日志与区块不一致（拉取期间链发生变化）时重新拉取
+func (s *Synchronizer) fetchRange(ctx context.Context, start, end uint64, batched bool) (*BlockBatch, error) {
+	for attempt := 1; ; attempt++ {
+		var blocks []*models.Block
+		var err error
+		if batched {
+			blocks, err = s.fetchBlocksBatch(ctx, start, end)
+		} else {
+			blocks, err = s.fetchBlocks(ctx, start, end)
+		}
+		if err != nil {
+			return nil, err
 		}
-		
-		block := &models.Block{
-			BlockNumber:  i,
-			BlockHash:    blockHash,
-			ParentHash:   parentHash,
-			TxRoot:       "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421",// 交易根哈希
-			ReceiptsRoot: "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421",// 收据根哈希
-			StateRoot:    "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421",// 状态根哈希
-			Miner:        minerAddress,// 矿工地址（从环境变量读取）
-			GasUsed:      21000 * uint64(i%10+1),
-			GasLimit:     10000000,
-			Time:         uint64(time.Now().Unix() - int64(i)),
-			Timestamp:    time.Unix(int64(uint64(time.Now().Unix())-i), 0),
-			ExtraData:    "0x",
-			Transactions: uint(i % 20),
+
+		txs, stale, err := s.fetchERC20Transactions(ctx, blocks)
+		if err != nil {
+			return nil, err
+		}
+		if !stale {
+			return &BlockBatch{Blocks: blocks, ERC20Transactions: txs}, nil
 		}
-		blockBatch.Blocks = append(blockBatch.Blocks, block)
+		if attempt == maxFetchAttempts {
+			return nil, fmt.Errorf("区块范围 %d-%d 的日志与区块哈希不一致，已重试 %d 次", start, end, attempt)
+		}
+		util.Log.Warn("日志与区块哈希不一致，重新拉取范围", "start", start, "end", end, "attempt", attempt)
 	}
-	
-	// 发送区块批次到通道
+}
+
+// emitBatch 校验区块与前一个区块衔接后发送到处理通道
+// 发现父哈希不一致时返回 reorged = true，调用方应丢弃后续数据：
+//   - 批次首个区块与已同步的区块不衔接：触发链重组处理，下一轮从分叉点之后重新同步
+//   - 批次内部不衔接（拉取期间链发生变化）：丢弃整个批次，下一轮从 lastBlockNum 重新拉取
+func (s *Synchronizer) emitBatch(ctx context.Context, blockBatch *BlockBatch) (reorged bool, err error) {
+	blocks := blockBatch.Blocks
+	if len(blocks) == 0 {
+		return false, nil
+	}
+
+	for i, block := range blocks {
+		linked, err := s.linksToParent(block, blocks[:i])
+		if err != nil {
+			return false, err
+		}
+		if linked {
+			continue
+		}
+		if i > 0 {
+			util.Log.Warn("批次内区块父哈希不一致，丢弃批次后重新拉取", "start", blocks[0].BlockNumber, "detected", block.BlockNumber)
+			return true, nil
+		}
+		return true, s.handleReorg(ctx, block.BlockNumber)
+	}
+
 	select {
 	case s.blockChannel <- blockBatch:
-		util.Log.Info("成功发送区块批次到处理通道", "count", len(blockBatch.Blocks))
-		s.lastBlockNum = endBlock + 1 // 更新最后处理的区块号
+		util.Log.Info("成功发送区块批次到处理通道", "count", len(blocks), "erc20_txs", len(blockBatch.ERC20Transactions))
+		for _, block := range blockBatch.Blocks {
+			s.rememberHash(block.BlockNumber, block.BlockHash)
+		}
+		s.lastBlockNum = blocks[len(blocks)-1].BlockNumber + 1 // 更新最后处理的区块号
 	case <-ctx.Done():
-		return ctx.Err()
+		return false, ctx.Err()
 	}
-	
-	return nil
+	return false, nil
+}
+
+// linksToParent 校验区块的父哈希是否等于前一个区块的哈希
+// 前一个区块优先取本批次中已拉取的区块，其次取已同步的记录；没有记录时不做校验
+func (s *Synchronizer) linksToParent(block *models.Block, pending []*models.Block) (bool, error) {
+	if len(pending) > 0 {
+		return pending[len(pending)-1].BlockHash == block.ParentHash, nil
+	}
+	if block.BlockNumber == 0 {
+		return true, nil
+	}
+	parentHash, ok, err := s.blockHash(block.BlockNumber - 1)
+	if err != nil || !ok {
+		return true, err
+	}
+	return parentHash == block.ParentHash, nil
+}
+
+// fetchBlocks 逐个拉取 [start, end] 范围内的区块
+func (s *Synchronizer) fetchBlocks(ctx context.Context, start, end uint64) ([]*models.Block, error) {
+	blocks := make([]*models.Block, 0, end-start+1)
+	for i := start; i <= end; i++ {
+		block, err := s.fetchBlock(ctx, i)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// fetchBlocksBatch 以 JSON-RPC 批量请求拉取 [start, end] 范围内的区块
+func (s *Synchronizer) fetchBlocksBatch(ctx context.Context, start, end uint64) ([]*models.Block, error) {
+	numbers := make([]uint64, 0, end-start+1)
+	for i := start; i <= end; i++ {
+		numbers = append(numbers, i)
+	}
+	summaries, err := s.ethClient.BatchBlockSummaries(ctx, numbers)
+	if err != nil {
+		return nil, fmt.Errorf("批量获取区块头失败（%d-%d）: %w", start, end, err)
+	}
+
+	blocks := make([]*models.Block, 0, len(summaries))
+	for _, summary := range summaries {
+		blocks = append(blocks, blockFromHeader(summary.Header, summary.TxCount))
+	}
+	return blocks, nil
+}
+
+// fetchBlock 拉取指定高度的区块头并构造区块实体
+func (s *Synchronizer) fetchBlock(ctx context.Context, number uint64) (*models.Block, error) {
+	header, err := s.ethClient.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return nil, fmt.Errorf("获取区块头失败（区块号: %d）: %w", number, err)
+	}
+
+	// 交易根为空树根时无需额外查询交易数量
+	txCount := 0
+	if header.TxHash != types.EmptyTxsHash {
+		count, err := s.ethClient.TransactionCount(ctx, header.Hash())
+		if err != nil {
+			return nil, fmt.Errorf("获取区块交易数量失败（区块号: %d）: %w", number, err)
+		}
+		txCount = int(count)
+	}
+
+	return blockFromHeader(header, txCount), nil
+}
+
+// blockFromHeader 根据区块头构造区块实体
+func blockFromHeader(header *types.Header, txCount int) *models.Block {
+	return models.NewBlockFromRPC(header.Number.Uint64(), header.Hash(), header.ParentHash,
+		header.TxHash, header.ReceiptHash, header.Root,
+		header.Coinbase, header.GasUsed, header.GasLimit, header.Time,
+		header.Extra, txCount)
 }
 
 // Close 停止同步器
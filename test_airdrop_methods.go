@@ -3,8 +3,11 @@ package main
 import (
 	"context"
 	"fmt"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 	"go-contracts/config"
 	"go-contracts/contract"
 	"go-contracts/database"
@@ -28,16 +31,16 @@ func (m *mockEthClientImpl) ERC20Allowance(ctx context.Context, contractAddress,
 	return big.NewInt(0), nil
 }
 
-func (m *mockEthClientImpl) ERC20Approve(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, spender common.Address, value *big.Int) (*big.Int, error) {
-	return big.NewInt(0), nil
+func (m *mockEthClientImpl) ERC20Approve(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, spender common.Address, value *big.Int) (*types.Transaction, error) {
+	return nil, nil
 }
 
-func (m *mockEthClientImpl) ERC20Transfer(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, to common.Address, value *big.Int) (*big.Int, error) {
-	return big.NewInt(0), nil
+func (m *mockEthClientImpl) ERC20Transfer(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, to common.Address, value *big.Int) (*types.Transaction, error) {
+	return nil, nil
 }
 
-func (m *mockEthClientImpl) ERC20TransferFrom(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, from, to common.Address, value *big.Int) (*big.Int, error) {
-	return big.NewInt(0), nil
+func (m *mockEthClientImpl) ERC20TransferFrom(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, from, to common.Address, value *big.Int) (*types.Transaction, error) {
+	return nil, nil
 }
 
 func (m *mockEthClientImpl) ERC20Balance(ctx context.Context, contractAddress common.Address, account common.Address) (*big.Int, error) {
@@ -52,6 +55,120 @@ func (m *mockEthClientImpl) ERC20TokenInfo(ctx context.Context, contractAddress
 	return "MockToken", "MOCK", 18, nil
 }
 
+func (m *mockEthClientImpl) ChainID(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(56), nil
+}
+
+func (m *mockEthClientImpl) BlockNumber(ctx context.Context) (uint64, error) {
+	return 0, nil
+}
+
+func (m *mockEthClientImpl) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Number: big.NewInt(0)}, nil
+}
+
+func (m *mockEthClientImpl) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	return &types.Header{Number: big.NewInt(0)}, nil
+}
+
+func (m *mockEthClientImpl) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return nil, ethereum.NotFound
+}
+
+func (m *mockEthClientImpl) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return nil, ethereum.NotFound
+}
+
+func (m *mockEthClientImpl) TransactionCount(ctx context.Context, blockHash common.Hash) (uint, error) {
+	return 0, nil
+}
+
+func (m *mockEthClientImpl) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	return nil, false, ethereum.NotFound
+}
+
+func (m *mockEthClientImpl) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return nil, ethereum.NotFound
+}
+
+func (m *mockEthClientImpl) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return nil, nil
+}
+
+func (m *mockEthClientImpl) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return nil, rpc.ErrNotificationsUnsupported
+}
+
+func (m *mockEthClientImpl) SupportsSubscriptions() bool {
+	return false
+}
+
+func (m *mockEthClientImpl) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+func (m *mockEthClientImpl) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return 0, nil
+}
+
+func (m *mockEthClientImpl) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return 0, nil
+}
+
+func (m *mockEthClientImpl) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+func (m *mockEthClientImpl) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+func (m *mockEthClientImpl) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return 0, nil
+}
+
+func (m *mockEthClientImpl) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (m *mockEthClientImpl) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return nil, nil
+}
+
+func (m *mockEthClientImpl) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (m *mockEthClientImpl) PendingCallContract(ctx context.Context, call ethereum.CallMsg) ([]byte, error) {
+	return nil, nil
+}
+
+func (m *mockEthClientImpl) PendingEstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return 0, nil
+}
+
+func (m *mockEthClientImpl) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return nil
+}
+
+func (m *mockEthClientImpl) BatchBlockSummaries(ctx context.Context, numbers []uint64) ([]*node.BlockSummary, error) {
+	return nil, nil
+}
+
+func (m *mockEthClientImpl) BatchTransactionReceipts(ctx context.Context, hashes []common.Hash) ([]*types.Receipt, error) {
+	return nil, nil
+}
+
+func (m *mockEthClientImpl) BatchCallContract(ctx context.Context, msgs []ethereum.CallMsg, blockNumber *big.Int) ([][]byte, error) {
+	return nil, nil
+}
+
+func (m *mockEthClientImpl) EndpointStats() []node.EndpointStats {
+	return nil
+}
+
+func (m *mockEthClientImpl) Close() {}
+
 func main() {
 	// 初始化日志
 	util.InitLogger()
@@ -97,7 +214,7 @@ func main() {
 	var ethClient node.EthClient = &mockEthClientImpl{}
 
 	// 创建服务实例
-	svc := service.New(validator, ethClient)
+	svc := service.New(validator, ethClient, nil, config.TxConfig{}, nil, nil)
 
 	// 直接测试服务层的方法
 	fmt.Println("===== 直接测试服务层方法 =====")
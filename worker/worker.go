@@ -6,6 +6,7 @@ import (
 	"go-contracts/contract"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"math/big"
 )
@@ -38,7 +39,7 @@ func (w *ERC20Worker) GetAllowance(ctx context.Context, contractAddress common.A
 }
 
 // Approve 设置授权
-func (w *ERC20Worker) Approve(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, spender common.Address, value *big.Int) (*big.Int, error) {
+func (w *ERC20Worker) Approve(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, spender common.Address, value *big.Int) (*types.Transaction, error) {
 	contract, err := w.GetContract(contractAddress)
 	if err != nil {
 		return nil, fmt.Errorf("获取合约实例失败: %w", err)
@@ -49,12 +50,11 @@ func (w *ERC20Worker) Approve(ctx context.Context, contractAddress common.Addres
 		return nil, fmt.Errorf("授权失败: %w", err)
 	}
 
-	// 返回交易哈希的字节表示作为ID
-	return new(big.Int).SetBytes(tx.Hash().Bytes()), nil
+	return tx, nil
 }
 
 // Transfer 转账
-func (w *ERC20Worker) Transfer(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, to common.Address, value *big.Int) (*big.Int, error) {
+func (w *ERC20Worker) Transfer(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, to common.Address, value *big.Int) (*types.Transaction, error) {
 	contract, err := w.GetContract(contractAddress)
 	if err != nil {
 		return nil, fmt.Errorf("获取合约实例失败: %w", err)
@@ -65,12 +65,11 @@ func (w *ERC20Worker) Transfer(ctx context.Context, contractAddress common.Addre
 		return nil, fmt.Errorf("转账失败: %w", err)
 	}
 
-	// 返回交易哈希的字节表示作为ID
-	return new(big.Int).SetBytes(tx.Hash().Bytes()), nil
+	return tx, nil
 }
 
 // TransferFrom 授权转账
-func (w *ERC20Worker) TransferFrom(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, from, to common.Address, value *big.Int) (*big.Int, error) {
+func (w *ERC20Worker) TransferFrom(ctx context.Context, contractAddress common.Address, auth *bind.TransactOpts, from, to common.Address, value *big.Int) (*types.Transaction, error) {
 	contract, err := w.GetContract(contractAddress)
 	if err != nil {
 		return nil, fmt.Errorf("获取合约实例失败: %w", err)
@@ -81,8 +80,7 @@ func (w *ERC20Worker) TransferFrom(ctx context.Context, contractAddress common.A
 		return nil, fmt.Errorf("授权转账失败: %w", err)
 	}
 
-	// 返回交易哈希的字节表示作为ID
-	return new(big.Int).SetBytes(tx.Hash().Bytes()), nil
+	return tx, nil
 }
 
 // GetBalance 查询余额